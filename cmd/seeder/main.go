@@ -11,6 +11,7 @@ import (
 	"github.com/campus-project-hub/api/internal/config"
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -21,6 +22,12 @@ import (
 const assetsDir = "./cmd/seeder/assets"
 const uploadsDir = "./uploads"
 
+// searchMigrations add the search columns and triggers that AutoMigrate cannot create
+var searchMigrations = []string{
+	"./migrations/000029_add_full_text_search.up.sql",
+	"./migrations/000030_add_author_name_to_search.up.sql",
+}
+
 func main() {
 	log.Println("🌱 Starting database seeder...")
 
@@ -47,12 +54,21 @@ func main() {
 		log.Fatalf("Failed to run AutoMigrate: %v", err)
 	}
 
+	// AutoMigrate cannot create the generated full-text search columns
+	log.Println("🔎 Adding full-text search columns...")
+	if err := addSearchColumns(db); err != nil {
+		log.Fatalf("Failed to add search columns: %v", err)
+	}
+
 	// Create uploads directory if not exists
 	if err := os.MkdirAll(uploadsDir, 0755); err != nil {
 		log.Fatalf("Failed to create uploads directory: %v", err)
 	}
 
 	// Seed data
+	log.Println("Seeding universities...")
+	seedUniversities(db)
+
 	log.Println("Seeding users...")
 	users := seedUsers(db)
 
@@ -74,15 +90,35 @@ func main() {
 func dropTables(db *gorm.DB) {
 	// Drop tables in reverse order of dependencies (junction tables first)
 	tables := []string{
+		"download_tokens",
+		"release_assets",
+		"project_releases",
+		"ownership_transfers",
+		"transaction_shares",
+		"project_collaborators",
+		"project_revisions",
+		"slug_redirects",
+		"audit_logs",
+		"login_attempts",
+		"personal_access_tokens",
+		"student_verifications",
+		"user_identities",
+		"recovery_codes",
+		"password_reset_tokens",
+		"auth_codes",
+		"sessions",
 		"project_likes",
 		"comments",
 		"transactions",
 		"project_images",
+		"project_technologies",
+		"technologies",
 		"projects",
 		"articles",
 		"reports",
 		"block_records",
 		"users",
+		"universities",
 		"categories",
 		"schema_migrations", // Also drop migrations table for fresh start
 	}
@@ -97,8 +133,10 @@ func dropTables(db *gorm.DB) {
 
 func runAutoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
+		&models.University{},
 		&models.User{},
 		&models.Category{},
+		&models.Technology{},
 		&models.Project{},
 		&models.ProjectImage{},
 		&models.ProjectLike{},
@@ -107,9 +145,40 @@ func runAutoMigrate(db *gorm.DB) error {
 		&models.Transaction{},
 		&models.Report{},
 		&models.BlockRecord{},
+		&models.Session{},
+		&models.AuthCode{},
+		&models.PasswordResetToken{},
+		&models.RecoveryCode{},
+		&models.UserIdentity{},
+		&models.StudentVerification{},
+		&models.PersonalAccessToken{},
+		&models.LoginAttempt{},
+		&models.AuditLog{},
+		&models.SlugRedirect{},
+		&models.ProjectRevision{},
+		&models.ProjectCollaborator{},
+		&models.TransactionShare{},
+		&models.OwnershipTransfer{},
+		&models.ProjectRelease{},
+		&models.ReleaseAsset{},
+		&models.DownloadToken{},
 	)
 }
 
+// addSearchColumns applies the full-text search migrations to the tables created by AutoMigrate
+func addSearchColumns(db *gorm.DB) error {
+	for _, path := range searchMigrations {
+		sql, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := db.Exec(string(sql)).Error; err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 // getAPIBaseURL returns the API base URL for constructing full image URLs
 func getAPIBaseURL() string {
 	cfg := config.GetConfig()
@@ -261,7 +330,10 @@ func seedUsers(db *gorm.DB) []models.User {
 		},
 	}
 
+	// Demo accounts are trusted as verified
+	verifiedAt := time.Now()
 	for i := range users {
+		users[i].EmailVerifiedAt = &verifiedAt
 		if err := db.Create(&users[i]).Error; err != nil {
 			log.Printf("Error creating user %s: %v", users[i].Email, err)
 		}
@@ -270,6 +342,24 @@ func seedUsers(db *gorm.DB) []models.User {
 	return users
 }
 
+func seedUniversities(db *gorm.DB) []models.University {
+	universities := []models.University{
+		{Name: "Universitas Indonesia", Slug: "universitas-indonesia", Domains: pq.StringArray{"ui.ac.id"}},
+		{Name: "Institut Teknologi Bandung", Slug: "institut-teknologi-bandung", Domains: pq.StringArray{"itb.ac.id"}},
+		{Name: "Universitas Gadjah Mada", Slug: "universitas-gadjah-mada", Domains: pq.StringArray{"ugm.ac.id"}},
+		{Name: "Institut Teknologi Sepuluh Nopember", Slug: "institut-teknologi-sepuluh-nopember", Domains: pq.StringArray{"its.ac.id"}},
+		{Name: "Universitas Brawijaya", Slug: "universitas-brawijaya", Domains: pq.StringArray{"ub.ac.id"}},
+	}
+
+	for i := range universities {
+		if err := db.Create(&universities[i]).Error; err != nil {
+			log.Printf("Error creating university %s: %v", universities[i].Name, err)
+		}
+	}
+
+	return universities
+}
+
 func seedCategories(db *gorm.DB) []models.Category {
 	categories := []models.Category{
 		{
@@ -431,6 +521,11 @@ func seedProjects(db *gorm.DB, users []models.User, categories []models.Category
 			log.Printf("Error creating project %s: %v", projects[i].Title, err)
 		}
 
+		// Link the tech stack to the technology taxonomy
+		if err := services.SetProjectTechnologies(db, &projects[i], projects[i].TechStack); err != nil {
+			log.Printf("Error linking technologies of project %s: %v", projects[i].Title, err)
+		}
+
 		// Add project images
 		images := []models.ProjectImage{
 			{
@@ -443,6 +538,17 @@ func seedProjects(db *gorm.DB, users []models.User, categories []models.Category
 		for j := range images {
 			db.Create(&images[j])
 		}
+
+		// The author owns the project and keeps all revenue
+		db.Create(&models.ProjectCollaborator{
+			ID:           uuid.New(),
+			ProjectID:    projects[i].ID,
+			UserID:       projects[i].UserID,
+			Role:         models.CollaboratorOwner,
+			Status:       models.CollaboratorAccepted,
+			RevenueShare: 100,
+			AcceptedAt:   &projects[i].CreatedAt,
+		})
 	}
 
 	return projects
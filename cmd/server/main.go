@@ -16,7 +16,7 @@
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer" followed by a space and JWT token.
+// @description Type "Bearer" followed by a space and a JWT or personal access token.
 
 package main
 
@@ -33,6 +33,9 @@ import (
 	"github.com/campus-project-hub/api/internal/config"
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/router"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/campus-project-hub/api/internal/worker"
 	"github.com/gin-gonic/gin"
 )
 
@@ -43,6 +46,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Refuse unsafe settings such as the sample JWT secret in production
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Load token signing keys
+	if err := utils.LoadSigningKeys(); err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
 	// Set Gin mode
 	if cfg.App.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -59,6 +72,30 @@ func main() {
 		log.Printf("Warning: Failed to create upload directory: %v", err)
 	}
 
+	// Start background jobs
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	worker.Start(workerCtx, worker.Job{
+		Name:     "account-deletion",
+		Interval: time.Hour,
+		Run: func() error {
+			purged, err := services.PurgeDueAccounts()
+			if purged > 0 {
+				log.Printf("Anonymized %d deleted accounts", purged)
+			}
+			return err
+		},
+	}, worker.Job{
+		Name:     "scheduled-publishing",
+		Interval: time.Minute,
+		Run: func() error {
+			published, err := services.PublishDueProjects()
+			if published > 0 {
+				log.Printf("Published %d scheduled projects", published)
+			}
+			return err
+		},
+	})
+
 	// Initialize router with all routes
 	r := router.Setup(cfg)
 
@@ -84,6 +121,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopWorkers()
 
 	// Create a deadline for shutdown (10 seconds)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -17,14 +20,21 @@ type Config struct {
 	Midtrans MidtransConfig
 	Upload   UploadConfig
 	CORS     CORSConfig
+	Mail     MailConfig
+	Auth     AuthConfig
+	Search   SearchConfig
 }
 
+// AppConfig holds the general server settings. TrustedProxies lists the proxy IPs or CIDR
+// ranges whose X-Forwarded-For header is believed; with none, the client IP is the address
+// of the connection.
 type AppConfig struct {
-	Name    string
-	Env     string
-	Port    int
-	Debug   bool
-	BaseURL string
+	Name           string
+	Env            string
+	Port           int
+	Debug          bool
+	BaseURL        string
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -40,11 +50,29 @@ type JWTConfig struct {
 	Secret             string
 	ExpiryHours        int
 	RefreshExpiryHours int
+	ActiveKeyID        string
+	Keys               []JWTKeyConfig
+}
+
+// JWTKeyConfig is an asymmetric key used to sign or verify tokens. Keys with only
+// a public key are kept for verification after a rotation.
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"id"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+// defaultJWTSecrets are the placeholder secrets shipped in the sample configuration
+var defaultJWTSecrets = []string{
+	"your-super-secret-jwt-key",
+	"your-super-secret-jwt-key-change-in-production",
 }
 
 type OAuthConfig struct {
-	Google OAuthProviderConfig
-	GitHub OAuthProviderConfig
+	Google              OAuthProviderConfig
+	GitHub              OAuthProviderConfig
+	OIDC                []OIDCProviderConfig
+	FrontendCallbackURL string
 }
 
 type OAuthProviderConfig struct {
@@ -53,21 +81,77 @@ type OAuthProviderConfig struct {
 	RedirectURL  string
 }
 
+// OIDCProviderConfig describes a generic OpenID Connect provider such as a campus SSO
+type OIDCProviderConfig struct {
+	Name         string           `mapstructure:"name"`
+	DisplayName  string           `mapstructure:"display_name"`
+	DiscoveryURL string           `mapstructure:"discovery_url"`
+	ClientID     string           `mapstructure:"client_id"`
+	ClientSecret string           `mapstructure:"client_secret"`
+	RedirectURL  string           `mapstructure:"redirect_url"`
+	Scopes       []string         `mapstructure:"scopes"`
+	Claims       OIDCClaimMapping `mapstructure:"claims"`
+}
+
+// OIDCClaimMapping names the claims that hold profile fields
+type OIDCClaimMapping struct {
+	Name       string `mapstructure:"name"`
+	Email      string `mapstructure:"email"`
+	University string `mapstructure:"university"`
+	Major      string `mapstructure:"major"`
+}
+
 type MidtransConfig struct {
 	ServerKey    string
 	ClientKey    string
 	IsProduction bool
 }
 
+// UploadConfig holds the public upload directory and the private storage for release
+// files, which is only served through signed download URLs
 type UploadConfig struct {
-	Dir     string
-	MaxSize int64
+	Dir                string
+	MaxSize            int64
+	PrivateDir         string
+	ReleaseMaxSize     int64
+	DownloadTTLMinutes int
 }
 
 type CORSConfig struct {
 	FrontendURL string
 }
 
+type MailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type AuthConfig struct {
+	RequireVerifiedEmail          bool
+	EmailVerificationTTLHours     int
+	PasswordResetTTLMinutes       int
+	TwoFactorRequiredRoles        []string
+	StudentVerificationTTLMinutes int
+	ImpersonationTTLMinutes       int
+	AccountDeletionGraceDays      int
+	// Login brute-force protection
+	LoginMaxAttempts          int
+	LoginMaxAttemptsPerIP     int
+	LoginAttemptWindowMinutes int
+	LoginLockoutBaseSeconds   int
+	LoginLockoutMaxMinutes    int
+	LoginAttemptStore         string
+}
+
+// SearchConfig holds the Postgres text search configuration used to parse search queries
+// and highlight results: "indonesian" or "english"
+type SearchConfig struct {
+	Language string
+}
+
 var AppConfig_ *Config
 
 func Load() (*Config, error) {
@@ -94,11 +178,12 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		App: AppConfig{
-			Name:    viper.GetString("app.name"),
-			Env:     viper.GetString("app.env"),
-			Port:    viper.GetInt("app.port"),
-			Debug:   viper.GetBool("app.debug"),
-			BaseURL: viper.GetString("app.base_url"),
+			Name:           viper.GetString("app.name"),
+			Env:            viper.GetString("app.env"),
+			Port:           viper.GetInt("app.port"),
+			Debug:          viper.GetBool("app.debug"),
+			BaseURL:        viper.GetString("app.base_url"),
+			TrustedProxies: splitList(viper.GetStringSlice("app.trusted_proxies")),
 		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("database.host"),
@@ -112,6 +197,7 @@ func Load() (*Config, error) {
 			Secret:             viper.GetString("jwt.secret"),
 			ExpiryHours:        viper.GetInt("jwt.expiry_hours"),
 			RefreshExpiryHours: viper.GetInt("jwt.refresh_expiry_hours"),
+			ActiveKeyID:        viper.GetString("jwt.active_key_id"),
 		},
 		OAuth: OAuthConfig{
 			Google: OAuthProviderConfig{
@@ -124,6 +210,7 @@ func Load() (*Config, error) {
 				ClientSecret: viper.GetString("oauth.github.client_secret"),
 				RedirectURL:  viper.GetString("oauth.github.redirect_url"),
 			},
+			FrontendCallbackURL: viper.GetString("oauth.frontend_callback_url"),
 		},
 		Midtrans: MidtransConfig{
 			ServerKey:    viper.GetString("midtrans.server_key"),
@@ -131,18 +218,49 @@ func Load() (*Config, error) {
 			IsProduction: viper.GetBool("midtrans.is_production"),
 		},
 		Upload: UploadConfig{
-			Dir:     viper.GetString("upload.dir"),
-			MaxSize: viper.GetInt64("upload.max_size"),
+			Dir:                viper.GetString("upload.dir"),
+			MaxSize:            viper.GetInt64("upload.max_size"),
+			PrivateDir:         viper.GetString("upload.private_dir"),
+			ReleaseMaxSize:     viper.GetInt64("upload.release_max_size"),
+			DownloadTTLMinutes: viper.GetInt("upload.download_ttl_minutes"),
 		},
 		CORS: CORSConfig{
 			FrontendURL: viper.GetString("cors.frontend_url"),
 		},
+		Mail: MailConfig{
+			Host:     viper.GetString("mail.host"),
+			Port:     viper.GetInt("mail.port"),
+			Username: viper.GetString("mail.username"),
+			Password: viper.GetString("mail.password"),
+			From:     viper.GetString("mail.from"),
+		},
+		Auth: AuthConfig{
+			RequireVerifiedEmail:          viper.GetBool("auth.require_verified_email"),
+			EmailVerificationTTLHours:     viper.GetInt("auth.email_verification_ttl_hours"),
+			PasswordResetTTLMinutes:       viper.GetInt("auth.password_reset_ttl_minutes"),
+			TwoFactorRequiredRoles:        splitList(viper.GetStringSlice("auth.two_factor_required_roles")),
+			StudentVerificationTTLMinutes: viper.GetInt("auth.student_verification_ttl_minutes"),
+			ImpersonationTTLMinutes:       viper.GetInt("auth.impersonation_ttl_minutes"),
+			AccountDeletionGraceDays:      viper.GetInt("auth.account_deletion_grace_days"),
+			LoginMaxAttempts:              viper.GetInt("auth.login_max_attempts"),
+			LoginMaxAttemptsPerIP:         viper.GetInt("auth.login_max_attempts_per_ip"),
+			LoginAttemptWindowMinutes:     viper.GetInt("auth.login_attempt_window_minutes"),
+			LoginLockoutBaseSeconds:       viper.GetInt("auth.login_lockout_base_seconds"),
+			LoginLockoutMaxMinutes:        viper.GetInt("auth.login_lockout_max_minutes"),
+			LoginAttemptStore:             viper.GetString("auth.login_attempt_store"),
+		},
+		Search: SearchConfig{
+			Language: viper.GetString("search.language"),
+		},
 	}
 
 	// Set defaults
 	if config.App.Port == 0 {
 		config.App.Port = 8000
 	}
+	if config.App.BaseURL == "" {
+		config.App.BaseURL = fmt.Sprintf("http://localhost:%d", config.App.Port)
+	}
 	if config.Database.Port == 0 {
 		config.Database.Port = 5432
 	}
@@ -152,12 +270,77 @@ func Load() (*Config, error) {
 	if config.JWT.RefreshExpiryHours == 0 {
 		config.JWT.RefreshExpiryHours = 168
 	}
+	if config.OAuth.FrontendCallbackURL == "" {
+		// Default to the callback page of the first configured frontend origin
+		config.OAuth.FrontendCallbackURL = config.FrontendBaseURL() + "/auth/callback"
+	}
+	if config.Mail.Port == 0 {
+		config.Mail.Port = 1025
+	}
+	if config.Mail.From == "" {
+		config.Mail.From = "no-reply@campus-project-hub.com"
+	}
+	if config.Auth.EmailVerificationTTLHours == 0 {
+		config.Auth.EmailVerificationTTLHours = 24
+	}
+	if config.Auth.PasswordResetTTLMinutes == 0 {
+		config.Auth.PasswordResetTTLMinutes = 60
+	}
+	if config.Auth.StudentVerificationTTLMinutes == 0 {
+		config.Auth.StudentVerificationTTLMinutes = 30
+	}
+	if config.Auth.ImpersonationTTLMinutes == 0 {
+		config.Auth.ImpersonationTTLMinutes = 30
+	}
+	if config.Auth.AccountDeletionGraceDays == 0 {
+		config.Auth.AccountDeletionGraceDays = 14
+	}
+	if config.Auth.LoginMaxAttempts == 0 {
+		config.Auth.LoginMaxAttempts = 5
+	}
+	if config.Auth.LoginMaxAttemptsPerIP == 0 {
+		config.Auth.LoginMaxAttemptsPerIP = 20
+	}
+	if config.Auth.LoginAttemptWindowMinutes == 0 {
+		config.Auth.LoginAttemptWindowMinutes = 15
+	}
+	if config.Auth.LoginLockoutBaseSeconds == 0 {
+		config.Auth.LoginLockoutBaseSeconds = 30
+	}
+	if config.Auth.LoginLockoutMaxMinutes == 0 {
+		config.Auth.LoginLockoutMaxMinutes = 60
+	}
+	if config.Auth.LoginAttemptStore == "" {
+		config.Auth.LoginAttemptStore = "memory"
+	}
+	if err := viper.UnmarshalKey("jwt.keys", &config.JWT.Keys); err != nil {
+		return nil, fmt.Errorf("error reading jwt keys: %w", err)
+	}
+
+	oidcProviders, err := loadOIDCProviders(config.App.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	config.OAuth.OIDC = oidcProviders
+
 	if config.Upload.Dir == "" {
 		config.Upload.Dir = "./uploads"
 	}
 	if config.Upload.MaxSize == 0 {
 		config.Upload.MaxSize = 10 * 1024 * 1024 // 10MB
 	}
+	if config.Upload.PrivateDir == "" {
+		config.Upload.PrivateDir = "./storage/releases"
+	}
+	if config.Upload.ReleaseMaxSize == 0 {
+		config.Upload.ReleaseMaxSize = 100 * 1024 * 1024 // 100MB
+	}
+	if config.Upload.DownloadTTLMinutes == 0 {
+		config.Upload.DownloadTTLMinutes = 5
+	}
+	if config.Search.Language == "" {
+		config.Search.Language = "indonesian"
+	}
 
 	AppConfig_ = config
 	return config, nil
@@ -170,6 +353,7 @@ func bindEnvVariables() {
 	viper.BindEnv("app.port", "APP_PORT")
 	viper.BindEnv("app.debug", "APP_DEBUG")
 	viper.BindEnv("app.base_url", "APP_BASE_URL")
+	viper.BindEnv("app.trusted_proxies", "APP_TRUSTED_PROXIES")
 
 	// Database
 	viper.BindEnv("database.host", "DB_HOST")
@@ -183,6 +367,7 @@ func bindEnvVariables() {
 	viper.BindEnv("jwt.secret", "JWT_SECRET")
 	viper.BindEnv("jwt.expiry_hours", "JWT_EXPIRY_HOURS")
 	viper.BindEnv("jwt.refresh_expiry_hours", "JWT_REFRESH_EXPIRY_HOURS")
+	viper.BindEnv("jwt.active_key_id", "JWT_ACTIVE_KEY_ID")
 
 	// OAuth - Google
 	viper.BindEnv("oauth.google.client_id", "GOOGLE_CLIENT_ID")
@@ -194,6 +379,9 @@ func bindEnvVariables() {
 	viper.BindEnv("oauth.github.client_secret", "GITHUB_CLIENT_SECRET")
 	viper.BindEnv("oauth.github.redirect_url", "GITHUB_REDIRECT_URL")
 
+	// OAuth - Frontend
+	viper.BindEnv("oauth.frontend_callback_url", "OAUTH_FRONTEND_CALLBACK_URL")
+
 	// Midtrans
 	viper.BindEnv("midtrans.server_key", "MIDTRANS_SERVER_KEY")
 	viper.BindEnv("midtrans.client_key", "MIDTRANS_CLIENT_KEY")
@@ -202,9 +390,130 @@ func bindEnvVariables() {
 	// Upload
 	viper.BindEnv("upload.dir", "UPLOAD_DIR")
 	viper.BindEnv("upload.max_size", "MAX_UPLOAD_SIZE")
+	viper.BindEnv("upload.private_dir", "UPLOAD_PRIVATE_DIR")
+	viper.BindEnv("upload.release_max_size", "MAX_RELEASE_SIZE")
+	viper.BindEnv("upload.download_ttl_minutes", "DOWNLOAD_URL_TTL_MINUTES")
 
 	// CORS
 	viper.BindEnv("cors.frontend_url", "FRONTEND_URL")
+
+	// Mail
+	viper.BindEnv("mail.host", "MAIL_HOST")
+	viper.BindEnv("mail.port", "MAIL_PORT")
+	viper.BindEnv("mail.username", "MAIL_USERNAME")
+	viper.BindEnv("mail.password", "MAIL_PASSWORD")
+	viper.BindEnv("mail.from", "MAIL_FROM")
+
+	// Auth
+	viper.BindEnv("auth.require_verified_email", "AUTH_REQUIRE_VERIFIED_EMAIL")
+	viper.BindEnv("auth.email_verification_ttl_hours", "AUTH_EMAIL_VERIFICATION_TTL_HOURS")
+	viper.BindEnv("auth.password_reset_ttl_minutes", "AUTH_PASSWORD_RESET_TTL_MINUTES")
+	viper.BindEnv("auth.two_factor_required_roles", "AUTH_TWO_FACTOR_REQUIRED_ROLES")
+	viper.BindEnv("auth.student_verification_ttl_minutes", "AUTH_STUDENT_VERIFICATION_TTL_MINUTES")
+	viper.BindEnv("auth.impersonation_ttl_minutes", "AUTH_IMPERSONATION_TTL_MINUTES")
+	viper.BindEnv("auth.account_deletion_grace_days", "AUTH_ACCOUNT_DELETION_GRACE_DAYS")
+	viper.BindEnv("auth.login_max_attempts", "AUTH_LOGIN_MAX_ATTEMPTS")
+	viper.BindEnv("auth.login_max_attempts_per_ip", "AUTH_LOGIN_MAX_ATTEMPTS_PER_IP")
+	viper.BindEnv("auth.login_attempt_window_minutes", "AUTH_LOGIN_ATTEMPT_WINDOW_MINUTES")
+	viper.BindEnv("auth.login_lockout_base_seconds", "AUTH_LOGIN_LOCKOUT_BASE_SECONDS")
+	viper.BindEnv("auth.login_lockout_max_minutes", "AUTH_LOGIN_LOCKOUT_MAX_MINUTES")
+	viper.BindEnv("auth.login_attempt_store", "AUTH_LOGIN_ATTEMPT_STORE")
+
+	// Search
+	viper.BindEnv("search.language", "SEARCH_LANGUAGE")
+}
+
+// loadOIDCProviders reads oauth.oidc from the config file. Client secrets can be kept
+// out of the file with OIDC_<NAME>_CLIENT_SECRET environment variables.
+func loadOIDCProviders(baseURL string) ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	if err := viper.UnmarshalKey("oauth.oidc", &providers); err != nil {
+		return nil, fmt.Errorf("error reading oidc providers: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for i := range providers {
+		p := &providers[i]
+		p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+		if p.Name == "" || p.DiscoveryURL == "" || p.ClientID == "" {
+			return nil, fmt.Errorf("oidc provider #%d needs name, discovery_url and client_id", i+1)
+		}
+		if p.Name == "google" || p.Name == "github" || seen[p.Name] {
+			return nil, fmt.Errorf("oidc provider name %q is already used", p.Name)
+		}
+		seen[p.Name] = true
+
+		envKey := "OIDC_" + strings.ToUpper(strings.ReplaceAll(p.Name, "-", "_")) + "_CLIENT_SECRET"
+		if secret := os.Getenv(envKey); secret != "" {
+			p.ClientSecret = secret
+		}
+		if p.DisplayName == "" {
+			p.DisplayName = p.Name
+		}
+		if p.RedirectURL == "" {
+			p.RedirectURL = strings.TrimRight(baseURL, "/") + "/api/v1/auth/oidc/" + p.Name + "/callback"
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "profile", "email"}
+		}
+		if p.Claims.Name == "" {
+			p.Claims.Name = "name"
+		}
+		if p.Claims.Email == "" {
+			p.Claims.Email = "email"
+		}
+	}
+
+	return providers, nil
+}
+
+// splitList accepts both YAML lists and comma separated environment values
+func splitList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if trimmed := strings.TrimSpace(item); trimmed != "" {
+				result = append(result, trimmed)
+			}
+		}
+	}
+	return result
+}
+
+// IsProduction reports whether the app runs in the production environment
+func (a *AppConfig) IsProduction() bool {
+	return a.Env == "production"
+}
+
+// Validate rejects invalid settings and settings that are unsafe to run in production
+func (c *Config) Validate() error {
+	if c.Auth.LoginAttemptStore != "memory" && c.Auth.LoginAttemptStore != "postgres" {
+		return fmt.Errorf("auth.login_attempt_store must be \"memory\" or \"postgres\", got %q", c.Auth.LoginAttemptStore)
+	}
+	for _, proxy := range c.App.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("app.trusted_proxies must hold IP addresses or CIDR ranges, got %q", proxy)
+			}
+		}
+	}
+	if c.Search.Language != "indonesian" && c.Search.Language != "english" {
+		return fmt.Errorf("search.language must be \"indonesian\" or \"english\", got %q", c.Search.Language)
+	}
+
+	if !c.App.IsProduction() {
+		return nil
+	}
+
+	// The secret still signs OAuth state cookies and email action tokens
+	if len(c.JWT.Secret) < 32 || slices.Contains(defaultJWTSecrets, c.JWT.Secret) {
+		return errors.New("JWT_SECRET must be set to a random value of at least 32 characters in production")
+	}
+	if len(c.JWT.Keys) == 0 {
+		return errors.New("jwt.keys must contain at least one signing key in production")
+	}
+
+	return nil
 }
 
 func (d *DatabaseConfig) DSN() string {
@@ -214,6 +523,25 @@ func (d *DatabaseConfig) DSN() string {
 	)
 }
 
+// OIDCProvider returns the configured OIDC provider with the given name
+func (o *OAuthConfig) OIDCProvider(name string) (*OIDCProviderConfig, bool) {
+	for i := range o.OIDC {
+		if o.OIDC[i].Name == name {
+			return &o.OIDC[i], true
+		}
+	}
+	return nil, false
+}
+
+// FrontendBaseURL returns the first configured frontend origin, used to build links in emails and redirects
+func (c *Config) FrontendBaseURL() string {
+	frontendURL := strings.TrimSpace(strings.Split(c.CORS.FrontendURL, ",")[0])
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+	return strings.TrimRight(frontendURL, "/")
+}
+
 func GetConfig() *Config {
 	if AppConfig_ == nil {
 		cfg, err := Load()
@@ -225,8 +553,11 @@ func GetConfig() *Config {
 	return AppConfig_
 }
 
-// EnsureUploadDir creates the upload directory if it doesn't exist
+// EnsureUploadDir creates the upload directories if they don't exist
 func EnsureUploadDir() error {
 	cfg := GetConfig()
-	return os.MkdirAll(cfg.Upload.Dir, 0755)
+	if err := os.MkdirAll(cfg.Upload.Dir, 0755); err != nil {
+		return err
+	}
+	return os.MkdirAll(cfg.Upload.PrivateDir, 0700)
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AccessTokenHandler struct{}
+
+func NewAccessTokenHandler() *AccessTokenHandler {
+	return &AccessTokenHandler{}
+}
+
+// List godoc
+// @Summary      List personal access tokens
+// @Description  Get the active personal access tokens of the current user
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Tokens and available scopes"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/tokens [get]
+func (h *AccessTokenHandler) List(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+
+	tokens, err := services.ListPersonalAccessTokens(currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil token akses")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"tokens": tokens,
+		"scopes": models.TokenScopes,
+	})
+}
+
+// Create godoc
+// @Summary      Create personal access token
+// @Description  Create a scoped token for scripts and CI. The token is only shown in this response.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.CreatePersonalAccessTokenInput true "Token name, scopes and expiry"
+// @Success      201 {object} map[string]interface{} "Created token with its plain value"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/tokens [post]
+func (h *AccessTokenHandler) Create(c *gin.Context) {
+	var input services.CreatePersonalAccessTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	token, plain, err := services.CreatePersonalAccessToken(currentUser.ID, &input)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Created(c, gin.H{
+		"token":       token,
+		"accessToken": plain,
+	})
+}
+
+// Revoke godoc
+// @Summary      Revoke personal access token
+// @Description  Revoke one of the current user's personal access tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Token ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Token revoked"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Token not found"
+// @Router       /auth/tokens/{id} [delete]
+func (h *AccessTokenHandler) Revoke(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.RevokePersonalAccessToken(currentUser.ID, id); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Token akses berhasil dicabut", nil)
+}
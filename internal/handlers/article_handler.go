@@ -7,6 +7,7 @@ import (
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/middleware"
 	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
 	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -27,7 +28,7 @@ func NewArticleHandler() *ArticleHandler {
 // @Produce      json
 // @Param        page query int false "Page number" default(1)
 // @Param        perPage query int false "Items per page" default(10)
-// @Param        search query string false "Search by title or excerpt"
+// @Param        search query string false "Full-text search in title, excerpt, content and author name; results are ordered by relevance"
 // @Param        category query string false "Filter by category"
 // @Param        userId query string false "Filter by user ID"
 // @Param        status query string false "Filter by status" default(published)
@@ -52,15 +53,14 @@ func (h *ArticleHandler) List(c *gin.Context) {
 
 	query := db.Model(&models.Article{}).Preload("User")
 
-	currentUser := middleware.GetCurrentUser(c)
-	if currentUser == nil || currentUser.Role == models.RoleUser {
+	if !middleware.Has(c, policy.ArticleReadAny) {
 		query = query.Where("status = ?", models.ArticleStatusPublished)
 	} else if status != "" {
 		query = query.Where("status = ?", status)
 	}
 
 	if search != "" {
-		query = query.Where("title ILIKE ? OR excerpt ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Scopes(services.SearchScope("articles", search))
 	}
 	if category != "" {
 		query = query.Where("category = ?", category)
@@ -73,6 +73,10 @@ func (h *ArticleHandler) List(c *gin.Context) {
 	query.Count(&total)
 
 	var articles []models.Article
+	// The most relevant matches come first
+	if search != "" {
+		query = query.Order(services.SearchRankOrder)
+	}
 	query.Offset((page - 1) * perPage).Limit(perPage).Order("published_at DESC").Find(&articles)
 
 	responses := make([]models.ArticleResponse, len(articles))
@@ -108,8 +112,7 @@ func (h *ArticleHandler) Get(c *gin.Context) {
 		return
 	}
 
-	currentUser := middleware.GetCurrentUser(c)
-	if article.Status == models.ArticleStatusBlocked && (currentUser == nil || currentUser.Role == models.RoleUser) {
+	if article.Status == models.ArticleStatusBlocked && !middleware.Has(c, policy.ArticleReadAny) {
 		utils.NotFound(c, "Artikel tidak ditemukan")
 		return
 	}
@@ -214,7 +217,6 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 		return
 	}
 
-	currentUser := middleware.GetCurrentUser(c)
 	db := database.GetDB()
 
 	var article models.Article
@@ -223,7 +225,7 @@ func (h *ArticleHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if article.UserID != currentUser.ID && currentUser.Role != models.RoleAdmin {
+	if !middleware.Can(c, policy.ActionUpdate, &article) {
 		utils.Forbidden(c, "Tidak diizinkan mengubah artikel ini")
 		return
 	}
@@ -281,7 +283,6 @@ func (h *ArticleHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	currentUser := middleware.GetCurrentUser(c)
 	db := database.GetDB()
 
 	var article models.Article
@@ -290,7 +291,7 @@ func (h *ArticleHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if article.UserID != currentUser.ID && currentUser.Role != models.RoleAdmin {
+	if !middleware.Can(c, policy.ActionDelete, &article) {
 		utils.Forbidden(c, "Tidak diizinkan menghapus artikel ini")
 		return
 	}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditLogHandler struct{}
+
+func NewAuditLogHandler() *AuditLogHandler {
+	return &AuditLogHandler{}
+}
+
+// List godoc
+// @Summary      List audit logs
+// @Description  Get paginated audit log entries such as impersonation start and stop (admin only)
+// @Tags         audit
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page query int false "Page number" default(1)
+// @Param        perPage query int false "Items per page" default(20)
+// @Param        action query string false "Filter by action, e.g. impersonation.start"
+// @Param        actorId query string false "Filter by actor user ID" format(uuid)
+// @Param        targetId query string false "Filter by target ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Paginated audit logs"
+// @Failure      400 {object} map[string]interface{} "Invalid filter"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /audit-logs [get]
+func (h *AuditLogHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	filter := services.AuditLogFilter{Action: c.Query("action")}
+	if actorID := c.Query("actorId"); actorID != "" {
+		id, err := uuid.Parse(actorID)
+		if err != nil {
+			utils.BadRequest(c, "Actor ID tidak valid")
+			return
+		}
+		filter.ActorID = &id
+	}
+	if targetID := c.Query("targetId"); targetID != "" {
+		id, err := uuid.Parse(targetID)
+		if err != nil {
+			utils.BadRequest(c, "Target ID tidak valid")
+			return
+		}
+		filter.TargetID = &id
+	}
+
+	logs, total, err := services.ListAuditLogs(filter, page, perPage)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat audit log")
+		return
+	}
+
+	utils.Paginated(c, logs, total, page, perPage)
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 
+	"github.com/campus-project-hub/api/internal/config"
 	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
 	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
 )
 
 type AuthHandler struct{}
@@ -15,6 +21,117 @@ func NewAuthHandler() *AuthHandler {
 	return &AuthHandler{}
 }
 
+const oauthStateCookie = "oauth_state"
+
+// setOAuthStateCookie stores the state and PKCE verifier in a signed cookie
+// that finishOAuth checks when the provider redirects back
+func setOAuthStateCookie(c *gin.Context, state *services.OAuthState) error {
+	value, err := state.Encode()
+	if err != nil {
+		return err
+	}
+
+	cfg := config.GetConfig()
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, value, int(services.OAuthStateTTL.Seconds()), "/", "", cfg.App.Env == "production", true)
+	return nil
+}
+
+// startOAuth stores a fresh state and redirects the user to the provider login page
+func startOAuth(c *gin.Context, oauthConfig *oauth2.Config) {
+	state, err := services.NewOAuthState(c.Query("redirect"))
+	if err == nil {
+		err = setOAuthStateCookie(c, state)
+	}
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memulai login")
+		return
+	}
+
+	c.Redirect(http.StatusTemporaryRedirect, state.AuthCodeURL(oauthConfig))
+}
+
+// finishOAuth validates the state returned by the provider against the cookie.
+// The cookie is cleared so the state cannot be used twice.
+func finishOAuth(c *gin.Context) (*services.OAuthState, error) {
+	value, err := c.Cookie(oauthStateCookie)
+	cfg := config.GetConfig()
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, "/", "", cfg.App.Env == "production", true)
+	if err != nil {
+		return nil, errors.New("state tidak ditemukan, silakan coba lagi")
+	}
+
+	return services.DecodeOAuthState(value, c.Query("state"))
+}
+
+// completeOAuth logs the user in, or links the provider account when the flow was
+// started from the profile, and redirects back to the frontend. Logins receive a
+// single-use code instead of the tokens themselves.
+func completeOAuth(c *gin.Context, userInfo *services.OAuthUserInfo, state *services.OAuthState) {
+	query := url.Values{}
+	query.Set("redirect", state.Redirect)
+
+	if state.LinkUserID != "" {
+		userID, err := uuid.Parse(state.LinkUserID)
+		if err == nil {
+			err = services.LinkIdentity(userID, userInfo)
+		}
+		if err != nil {
+			query.Set("error", err.Error())
+		} else {
+			query.Set("linked", userInfo.Provider)
+		}
+		redirectToFrontend(c, query)
+		return
+	}
+
+	user, err := services.FindOrCreateOAuthUser(userInfo)
+	if err != nil {
+		query.Set("error", err.Error())
+		redirectToFrontend(c, query)
+		return
+	}
+
+	code, err := services.CreateAuthCode(user.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal menyelesaikan login")
+		return
+	}
+
+	query.Set("code", code)
+	redirectToFrontend(c, query)
+}
+
+func redirectToFrontend(c *gin.Context, query url.Values) {
+	cfg := config.GetConfig()
+	c.Redirect(http.StatusTemporaryRedirect, cfg.OAuth.FrontendCallbackURL+"?"+query.Encode())
+}
+
+// respondAuthResult returns the token pair, or the 2FA challenge when a second factor is required
+func respondAuthResult(c *gin.Context, result *services.AuthResult) {
+	if result.TwoFactorRequired {
+		utils.Success(c, gin.H{
+			"twoFactorRequired": true,
+			"challengeToken":    result.ChallengeToken,
+		})
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"user":  result.User.ToResponse(),
+		"token": result.TokenPair,
+	})
+}
+
+// clientInfo collects the device details stored with a login session
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
 // Register godoc
 // @Summary      Register new user
 // @Description  Create a new user account with email and password
@@ -41,7 +158,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	result, err := services.Register(&input)
+	result, err := services.Register(&input, clientInfo(c))
 	if err != nil {
 		utils.BadRequest(c, err.Error())
 		return
@@ -60,9 +177,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        request body services.LoginInput true "Login credentials"
-// @Success      200 {object} map[string]interface{} "Successfully logged in"
+// @Success      200 {object} map[string]interface{} "Successfully logged in, or 2FA challenge"
 // @Failure      400 {object} map[string]interface{} "Invalid input"
 // @Failure      401 {object} map[string]interface{} "Invalid credentials"
+// @Failure      429 {object} map[string]interface{} "Too many failed attempts"
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var input services.LoginInput
@@ -80,16 +198,23 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := services.Login(&input)
+	result, err := services.Login(&input, clientInfo(c))
 	if err != nil {
-		utils.Unauthorized(c, err.Error())
+		respondLoginError(c, err)
 		return
 	}
 
-	utils.Success(c, gin.H{
-		"user":  result.User.ToResponse(),
-		"token": result.TokenPair,
-	})
+	respondAuthResult(c, result)
+}
+
+// respondLoginError answers a lockout with 429 and Retry-After, and any other login error with 401
+func respondLoginError(c *gin.Context, err error) {
+	var locked *services.LoginLockedError
+	if errors.As(err, &locked) {
+		utils.TooManyRequests(c, locked.RetryAfterSeconds(), locked.Error())
+		return
+	}
+	utils.Unauthorized(c, err.Error())
 }
 
 // RefreshToken godoc
@@ -112,7 +237,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := services.RefreshTokens(input.RefreshToken)
+	tokenPair, err := services.RefreshTokens(input.RefreshToken, clientInfo(c))
 	if err != nil {
 		utils.Unauthorized(c, err.Error())
 		return
@@ -142,10 +267,19 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 
 	stats := services.GetUserGamificationStats(user.TotalExp)
 
-	utils.Success(c, gin.H{
+	response := gin.H{
 		"user":         user.ToResponse(),
 		"gamification": stats,
-	})
+	}
+	if user.DeletionScheduledAt != nil {
+		response["deletionScheduledAt"] = user.DeletionScheduledAt
+	}
+	// Lets the frontend show that an admin is acting as this user
+	if impersonatorID := middleware.GetImpersonatorID(c); impersonatorID != uuid.Nil {
+		response["impersonatedBy"] = impersonatorID
+	}
+
+	utils.Success(c, response)
 }
 
 // GoogleAuth godoc
@@ -153,12 +287,11 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 // @Description  Initiate Google OAuth flow
 // @Tags         auth
 // @Produce      json
+// @Param        redirect query string false "Frontend path to return to after login"
 // @Success      307 {string} string "Redirect to Google"
 // @Router       /auth/google [get]
 func (h *AuthHandler) GoogleAuth(c *gin.Context) {
-	oauthConfig := services.GetGoogleOAuthConfig()
-	url := oauthConfig.AuthCodeURL("state")
-	c.Redirect(http.StatusTemporaryRedirect, url)
+	startOAuth(c, services.GetGoogleOAuthConfig())
 }
 
 // GoogleCallback godoc
@@ -167,33 +300,31 @@ func (h *AuthHandler) GoogleAuth(c *gin.Context) {
 // @Tags         auth
 // @Produce      json
 // @Param        code query string true "Authorization code"
-// @Success      307 {string} string "Redirect to frontend with token"
-// @Failure      400 {object} map[string]interface{} "Missing code"
+// @Param        state query string true "OAuth state"
+// @Success      307 {string} string "Redirect to frontend with one-time code, link result or error"
+// @Failure      400 {object} map[string]interface{} "Missing code or invalid state"
 // @Failure      500 {object} map[string]interface{} "OAuth error"
 // @Router       /auth/google/callback [get]
 func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+	state, err := finishOAuth(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
 	code := c.Query("code")
 	if code == "" {
 		utils.BadRequest(c, "Kode otorisasi tidak ditemukan")
 		return
 	}
 
-	userInfo, err := services.GetGoogleUserInfo(code)
+	userInfo, err := services.GetGoogleUserInfo(code, state.Verifier)
 	if err != nil {
 		utils.InternalServerError(c, "Gagal mendapatkan info user dari Google")
 		return
 	}
 
-	result, err := services.AuthenticateOAuthUser(userInfo)
-	if err != nil {
-		utils.InternalServerError(c, err.Error())
-		return
-	}
-
-	// Redirect to frontend with token
-	frontendURL := "http://localhost:3000/auth/callback"
-	c.Redirect(http.StatusTemporaryRedirect,
-		frontendURL+"?token="+result.TokenPair.AccessToken+"&refresh="+result.TokenPair.RefreshToken)
+	completeOAuth(c, userInfo, state)
 }
 
 // GitHubAuth godoc
@@ -201,12 +332,11 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 // @Description  Initiate GitHub OAuth flow
 // @Tags         auth
 // @Produce      json
+// @Param        redirect query string false "Frontend path to return to after login"
 // @Success      307 {string} string "Redirect to GitHub"
 // @Router       /auth/github [get]
 func (h *AuthHandler) GitHubAuth(c *gin.Context) {
-	oauthConfig := services.GetGitHubOAuthConfig()
-	url := oauthConfig.AuthCodeURL("state")
-	c.Redirect(http.StatusTemporaryRedirect, url)
+	startOAuth(c, services.GetGitHubOAuthConfig())
 }
 
 // GitHubCallback godoc
@@ -215,44 +345,432 @@ func (h *AuthHandler) GitHubAuth(c *gin.Context) {
 // @Tags         auth
 // @Produce      json
 // @Param        code query string true "Authorization code"
-// @Success      307 {string} string "Redirect to frontend with token"
-// @Failure      400 {object} map[string]interface{} "Missing code"
+// @Param        state query string true "OAuth state"
+// @Success      307 {string} string "Redirect to frontend with one-time code, link result or error"
+// @Failure      400 {object} map[string]interface{} "Missing code or invalid state"
 // @Failure      500 {object} map[string]interface{} "OAuth error"
 // @Router       /auth/github/callback [get]
 func (h *AuthHandler) GitHubCallback(c *gin.Context) {
+	state, err := finishOAuth(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
 	code := c.Query("code")
 	if code == "" {
 		utils.BadRequest(c, "Kode otorisasi tidak ditemukan")
 		return
 	}
 
-	userInfo, err := services.GetGitHubUserInfo(code)
+	userInfo, err := services.GetGitHubUserInfo(code, state.Verifier)
 	if err != nil {
 		utils.InternalServerError(c, "Gagal mendapatkan info user dari GitHub")
 		return
 	}
 
-	result, err := services.AuthenticateOAuthUser(userInfo)
+	completeOAuth(c, userInfo, state)
+}
+
+// JWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys that verify access and refresh tokens, selected by the kid header
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} map[string]interface{} "Key set"
+// @Router       /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	keys, err := utils.JWKS()
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat kunci")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// OIDCProviders godoc
+// @Summary      List OIDC providers
+// @Description  Get the configured OpenID Connect providers, e.g. campus SSO, for rendering login buttons
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} map[string]interface{} "Providers"
+// @Router       /auth/oidc [get]
+func (h *AuthHandler) OIDCProviders(c *gin.Context) {
+	utils.Success(c, gin.H{
+		"providers": services.ListOIDCProviders(),
+	})
+}
+
+// OIDCAuth godoc
+// @Summary      OIDC login
+// @Description  Initiate login with a configured OpenID Connect provider
+// @Tags         auth
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Param        redirect query string false "Frontend path to return to after login"
+// @Success      307 {string} string "Redirect to provider"
+// @Failure      404 {object} map[string]interface{} "Unknown provider"
+// @Failure      502 {object} map[string]interface{} "Provider discovery failed"
+// @Router       /auth/oidc/{provider} [get]
+func (h *AuthHandler) OIDCAuth(c *gin.Context) {
+	cfg := config.GetConfig()
+	if _, ok := cfg.OAuth.OIDCProvider(c.Param("provider")); !ok {
+		utils.NotFound(c, "Provider tidak ditemukan")
+		return
+	}
+
+	oauthConfig, err := services.GetOIDCOAuthConfig(c.Param("provider"))
 	if err != nil {
-		utils.InternalServerError(c, err.Error())
+		utils.Error(c, http.StatusBadGateway, "Provider login tidak dapat dihubungi")
 		return
 	}
 
-	// Redirect to frontend with token
-	frontendURL := "http://localhost:3000/auth/callback"
-	c.Redirect(http.StatusTemporaryRedirect,
-		frontendURL+"?token="+result.TokenPair.AccessToken+"&refresh="+result.TokenPair.RefreshToken)
+	startOAuth(c, oauthConfig)
+}
+
+// OIDCCallback godoc
+// @Summary      OIDC callback
+// @Description  Handle the callback of a configured OpenID Connect provider
+// @Tags         auth
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Param        code query string true "Authorization code"
+// @Param        state query string true "OAuth state"
+// @Success      307 {string} string "Redirect to frontend with one-time code, link result or error"
+// @Failure      400 {object} map[string]interface{} "Missing code or invalid state"
+// @Failure      404 {object} map[string]interface{} "Unknown provider"
+// @Failure      500 {object} map[string]interface{} "OAuth error"
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	provider := c.Param("provider")
+	cfg := config.GetConfig()
+	if _, ok := cfg.OAuth.OIDCProvider(provider); !ok {
+		utils.NotFound(c, "Provider tidak ditemukan")
+		return
+	}
+
+	state, err := finishOAuth(c)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		utils.BadRequest(c, "Kode otorisasi tidak ditemukan")
+		return
+	}
+
+	userInfo, err := services.GetOIDCUserInfo(provider, code, state)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mendapatkan info user dari provider")
+		return
+	}
+
+	completeOAuth(c, userInfo, state)
+}
+
+// Exchange godoc
+// @Summary      Exchange login code
+// @Description  Trade the one-time code from the OAuth callback redirect for a token pair
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body object{code=string} true "One-time login code"
+// @Success      200 {object} map[string]interface{} "User and token pair, or 2FA challenge"
+// @Failure      400 {object} map[string]interface{} "Code required"
+// @Failure      401 {object} map[string]interface{} "Invalid or expired code"
+// @Router       /auth/exchange [post]
+func (h *AuthHandler) Exchange(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" validate:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Code == "" {
+		utils.BadRequest(c, "Kode otorisasi diperlukan")
+		return
+	}
+
+	result, err := services.ExchangeAuthCode(input.Code, clientInfo(c))
+	if err != nil {
+		utils.Unauthorized(c, err.Error())
+		return
+	}
+
+	respondAuthResult(c, result)
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Verify the user's email address with the token from the verification email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body object{token=string} true "Verification token"
+// @Success      200 {object} map[string]interface{} "Email verified"
+// @Failure      400 {object} map[string]interface{} "Invalid or expired token"
+// @Router       /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var input struct {
+		Token string `json:"token" validate:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Token == "" {
+		utils.BadRequest(c, "Token verifikasi diperlukan")
+		return
+	}
+
+	user, err := services.VerifyEmail(input.Token)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Email berhasil diverifikasi", user.ToResponse())
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification email to the current user
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Verification email sent"
+// @Failure      400 {object} map[string]interface{} "Email already verified"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+
+	if err := services.ResendVerificationEmail(currentUser.ID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Email verifikasi telah dikirim", nil)
+}
+
+// ForgotPassword godoc
+// @Summary      Forgot password
+// @Description  Email a single-use password reset link if the account exists
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body services.ForgotPasswordInput true "Account email"
+// @Success      200 {object} map[string]interface{} "Reset email sent if the account exists"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var input services.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	if err := services.RequestPasswordReset(&input); err != nil {
+		utils.InternalServerError(c, "Gagal memproses permintaan reset password")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Jika email terdaftar, tautan reset password telah dikirim", nil)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with a reset token, signing out every session and revoking every personal access token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body services.ResetPasswordInput true "Reset token and new password"
+// @Success      200 {object} map[string]interface{} "Password reset"
+// @Failure      400 {object} map[string]interface{} "Invalid input or token"
+// @Router       /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var input services.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	if err := services.ResetPassword(&input); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Password berhasil diatur ulang, silakan login kembali", nil)
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Change the current user's password and sign out other sessions. Set revokeTokens to also revoke every personal access token. OAuth-only accounts may omit currentPassword to set their first password.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.ChangePasswordInput true "Current and new password"
+// @Success      200 {object} map[string]interface{} "Password changed"
+// @Failure      400 {object} map[string]interface{} "Invalid input or wrong current password"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var input services.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	sessionID := middleware.GetCurrentSessionID(c)
+
+	if err := services.ChangePassword(currentUser.ID, sessionID, &input); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Password berhasil diubah", nil)
 }
 
 // Logout godoc
 // @Summary      Logout user
-// @Description  Logout current user (client should clear token)
+// @Description  Logout current user and revoke the current session. Ends an impersonation when called with an impersonation token.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200 {object} map[string]interface{} "Logout successful"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
 // @Router       /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID := middleware.GetCurrentSessionID(c)
+	if middleware.GetImpersonatorID(c) != uuid.Nil {
+		if err := services.StopImpersonation(sessionID, clientInfo(c)); err != nil {
+			utils.InternalServerError(c, "Gagal mengakhiri impersonasi")
+			return
+		}
+		utils.SuccessWithMessage(c, "Impersonasi berakhir", nil)
+		return
+	}
+
+	if err := services.RevokeSession(sessionID); err != nil {
+		utils.InternalServerError(c, "Gagal logout")
+		return
+	}
+
 	utils.SuccessWithMessage(c, "Logout berhasil", nil)
 }
+
+// ListSessions godoc
+// @Summary      List active sessions
+// @Description  List every device/browser the current user is logged in from
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Active sessions"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+	currentSessionID := middleware.GetCurrentSessionID(c)
+
+	sessions, err := services.ListActiveSessions(currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil daftar sesi")
+		return
+	}
+
+	responses := make([]models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToResponse(currentSessionID)
+	}
+
+	utils.Success(c, gin.H{
+		"sessions": responses,
+	})
+}
+
+// RevokeSession godoc
+// @Summary      Revoke session
+// @Description  Sign out a single session of the current user
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Session ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Session revoked"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Session not found"
+// @Router       /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.RevokeUserSession(currentUser.ID, id); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Sesi berhasil dicabut", nil)
+}
+
+// RevokeOtherSessions godoc
+// @Summary      Revoke other sessions
+// @Description  Sign out every session of the current user except the current one
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Number of revoked sessions"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /auth/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+	currentSessionID := middleware.GetCurrentSessionID(c)
+
+	revoked, err := services.RevokeOtherSessions(currentUser.ID, currentSessionID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mencabut sesi")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Sesi lain berhasil dicabut", gin.H{
+		"revoked": revoked,
+	})
+}
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CollaboratorHandler struct{}
+
+func NewCollaboratorHandler() *CollaboratorHandler {
+	return &CollaboratorHandler{}
+}
+
+// loadTeamProject reads the project from the :id parameter with its accepted members.
+// On failure the response is already written.
+func loadTeamProject(c *gin.Context) (*models.Project, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return nil, false
+	}
+
+	var project models.Project
+	if err := database.GetDB().Scopes(services.WithCollaborators).First(&project, "id = ?", id).Error; err != nil {
+		utils.NotFound(c, "Project tidak ditemukan")
+		return nil, false
+	}
+	return &project, true
+}
+
+func toCollaboratorDetailResponses(collaborators []models.ProjectCollaborator) []models.CollaboratorDetailResponse {
+	responses := make([]models.CollaboratorDetailResponse, len(collaborators))
+	for i := range collaborators {
+		responses[i] = collaborators[i].ToDetailResponse()
+	}
+	return responses
+}
+
+// List godoc
+// @Summary      List project collaborators
+// @Description  Get the team of a project including pending invitations and revenue shares (members, admin or moderator)
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Collaborators"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/collaborators [get]
+func (h *CollaboratorHandler) List(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if _, member := project.CollaboratorRole(currentUser.ID); !member && !middleware.Has(c, policy.ProjectReadAny) {
+		utils.Forbidden(c, "Tidak diizinkan melihat tim project ini")
+		return
+	}
+
+	collaborators, err := services.ListCollaborators(project.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat kolaborator")
+		return
+	}
+
+	utils.Success(c, toCollaboratorDetailResponses(collaborators))
+}
+
+// Invite godoc
+// @Summary      Invite collaborator
+// @Description  Invite a user by username to join the project as owner, maintainer or contributor. The invitee has to accept (owners only).
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        request body services.InviteCollaboratorInput true "Invitee and role"
+// @Success      201 {object} map[string]interface{} "Pending invitation"
+// @Failure      400 {object} map[string]interface{} "Invalid input or already invited"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/collaborators [post]
+func (h *CollaboratorHandler) Invite(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if !middleware.Can(c, policy.ActionManage, project) {
+		utils.Forbidden(c, "Tidak diizinkan mengelola tim project ini")
+		return
+	}
+
+	var input services.InviteCollaboratorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	collaborator, err := services.InviteCollaborator(project, currentUser.ID, &input)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Created(c, collaborator.ToDetailResponse())
+}
+
+// Accept godoc
+// @Summary      Accept collaboration invitation
+// @Description  Join the project team the current user was invited to
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Invitation accepted"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Invitation not found"
+// @Router       /projects/{id}/collaborators/accept [post]
+func (h *CollaboratorHandler) Accept(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.AcceptInvitation(id, currentUser.ID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Undangan berhasil diterima", nil)
+}
+
+// UpdateCollaboratorRoleInput changes a member's role
+type UpdateCollaboratorRoleInput struct {
+	Role string `json:"role" validate:"required,oneof=owner maintainer contributor"`
+}
+
+// UpdateRole godoc
+// @Summary      Change collaborator role
+// @Description  Change the role of a team member. Members who stop being owners hand their revenue share back to the project's author (owners only).
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        userId path string true "Member user ID" format(uuid)
+// @Param        request body UpdateCollaboratorRoleInput true "New role"
+// @Success      200 {object} map[string]interface{} "Updated team"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Collaborator not found"
+// @Router       /projects/{id}/collaborators/{userId} [put]
+func (h *CollaboratorHandler) UpdateRole(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	if !middleware.Can(c, policy.ActionManage, project) {
+		utils.Forbidden(c, "Tidak diizinkan mengelola tim project ini")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		utils.BadRequest(c, "ID user tidak valid")
+		return
+	}
+
+	var input UpdateCollaboratorRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	if err := services.UpdateCollaboratorRole(project, userID, models.CollaboratorRole(input.Role)); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	collaborators, err := services.ListCollaborators(project.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat kolaborator")
+		return
+	}
+
+	utils.Success(c, toCollaboratorDetailResponses(collaborators))
+}
+
+// Remove godoc
+// @Summary      Remove collaborator
+// @Description  Remove a team member or withdraw an invitation (owners only). Members may remove themselves to leave the team or decline an invitation.
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        userId path string true "Member user ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Collaborator removed"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Collaborator not found"
+// @Router       /projects/{id}/collaborators/{userId} [delete]
+func (h *CollaboratorHandler) Remove(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		utils.BadRequest(c, "ID user tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if userID != currentUser.ID && !middleware.Can(c, policy.ActionManage, project) {
+		utils.Forbidden(c, "Tidak diizinkan mengelola tim project ini")
+		return
+	}
+
+	if err := services.RemoveCollaborator(project, userID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Kolaborator berhasil dihapus", nil)
+}
+
+// UpdateRevenueSplit godoc
+// @Summary      Update revenue split
+// @Description  Set the percentage of each sale paid to every owner of the project. The percentages must add up to 100 and cover all owners. Past sales keep their split (project author only).
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        request body services.UpdateRevenueSplitInput true "Owner shares"
+// @Success      200 {object} map[string]interface{} "Updated team"
+// @Failure      400 {object} map[string]interface{} "Invalid split"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/revenue-split [put]
+func (h *CollaboratorHandler) UpdateRevenueSplit(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	// Only the author moves money between owners; co-owners and staff cannot
+	if project.UserID != middleware.GetCurrentUser(c).ID {
+		utils.Forbidden(c, "Hanya pemilik utama yang dapat mengubah pembagian pendapatan")
+		return
+	}
+
+	var input services.UpdateRevenueSplitInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	collaborators, err := services.UpdateRevenueSplit(project, &input)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Pembagian pendapatan berhasil diperbarui", toCollaboratorDetailResponses(collaborators))
+}
+
+// Invitations godoc
+// @Summary      List my invitations
+// @Description  Get the pending project invitations of the current user
+// @Tags         collaborators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Pending invitations"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/invitations [get]
+func (h *CollaboratorHandler) Invitations(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+
+	invitations, err := services.ListInvitations(currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat undangan")
+		return
+	}
+
+	responses := make([]models.InvitationResponse, len(invitations))
+	for i := range invitations {
+		responses[i] = invitations[i].ToInvitationResponse()
+	}
+
+	utils.Success(c, responses)
+}
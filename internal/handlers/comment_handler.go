@@ -6,6 +6,7 @@ import (
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/middleware"
 	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
 	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -112,7 +113,7 @@ func (h *CommentHandler) Create(c *gin.Context) {
 
 	// Check if project exists
 	var project models.Project
-	if err := db.First(&project, "id = ?", projectID).Error; err != nil {
+	if err := db.First(&project, "id = ?", projectID).Error; err != nil || !project.IsPublished() {
 		utils.NotFound(c, "Project tidak ditemukan")
 		return
 	}
@@ -159,7 +160,6 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	currentUser := middleware.GetCurrentUser(c)
 	db := database.GetDB()
 
 	var comment models.Comment
@@ -168,13 +168,8 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Allow delete by: comment owner, project owner, admin, or moderator
-	canDelete := comment.UserID == currentUser.ID ||
-		comment.Project.UserID == currentUser.ID ||
-		currentUser.Role == models.RoleAdmin ||
-		currentUser.Role == models.RoleModerator
-
-	if !canDelete {
+	// Allow delete by: comment owner, project owner, or comment.delete.any
+	if !middleware.Can(c, policy.ActionDelete, &comment) {
 		utils.Forbidden(c, "Tidak diizinkan menghapus komentar ini")
 		return
 	}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type IdentityHandler struct{}
+
+func NewIdentityHandler() *IdentityHandler {
+	return &IdentityHandler{}
+}
+
+// List godoc
+// @Summary      List linked identities
+// @Description  Get the login providers linked to the current user
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Linked identities"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/identities [get]
+func (h *IdentityHandler) List(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+
+	identities, err := services.ListIdentities(currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil akun terhubung")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"identities":  identities,
+		"hasPassword": currentUser.PasswordHash != nil,
+	})
+}
+
+// Link godoc
+// @Summary      Start linking a provider
+// @Description  Start the provider login in link mode for the current user. The response sets the OAuth state cookie, so call it with credentials from the browser and then open the returned URL.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        provider path string true "Provider: google, github or a configured OIDC provider"
+// @Param        redirect query string false "Frontend path to return to"
+// @Success      200 {object} map[string]interface{} "Provider authorization URL"
+// @Failure      400 {object} map[string]interface{} "Unsupported provider"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      502 {object} map[string]interface{} "Provider unreachable"
+// @Router       /auth/identities/link/{provider} [post]
+func (h *IdentityHandler) Link(c *gin.Context) {
+	provider := c.Param("provider")
+	if !services.IsSupportedOAuthProvider(provider) {
+		utils.BadRequest(c, "Provider tidak didukung")
+		return
+	}
+
+	oauthConfig, err := services.GetProviderOAuthConfig(provider)
+	if err != nil {
+		utils.Error(c, http.StatusBadGateway, "Provider login tidak dapat dihubungi")
+		return
+	}
+
+	// The state is bound to this authenticated request, so a link can only be
+	// completed in the browser of the user who started it
+	state, err := services.NewOAuthState(c.Query("redirect"))
+	if err == nil {
+		state.LinkUserID = middleware.GetCurrentUser(c).ID.String()
+		err = setOAuthStateCookie(c, state)
+	}
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memulai penautan akun")
+		return
+	}
+
+	utils.Success(c, gin.H{"url": state.AuthCodeURL(oauthConfig)})
+}
+
+// Unlink godoc
+// @Summary      Unlink identity
+// @Description  Remove a linked provider. The last login method cannot be removed.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Identity ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Identity unlinked"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or last login method"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/identities/{id} [delete]
+func (h *IdentityHandler) Unlink(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.UnlinkIdentity(currentUser.ID, id); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Akun berhasil dilepas", nil)
+}
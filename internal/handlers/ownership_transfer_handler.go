@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OwnershipTransferHandler struct{}
+
+func NewOwnershipTransferHandler() *OwnershipTransferHandler {
+	return &OwnershipTransferHandler{}
+}
+
+// loadTransferResource reads the project or article from the :id parameter. On failure
+// the response is already written. canView tells whether the current user may see the
+// resource's ownership history: its editors and staff who can see hidden resources.
+func loadTransferResource(c *gin.Context, resourceType models.TransferResourceType) (resource services.TransferResource, canView bool, ok bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return resource, false, false
+	}
+
+	db := database.GetDB()
+
+	switch resourceType {
+	case models.TransferResourceProject:
+		var project models.Project
+		if err := db.Scopes(services.WithCollaborators).First(&project, "id = ?", id).Error; err != nil {
+			utils.NotFound(c, "Project tidak ditemukan")
+			return resource, false, false
+		}
+		resource = services.TransferResource{Type: resourceType, ID: project.ID, OwnerID: project.UserID, Title: project.Title}
+		canView = middleware.Can(c, policy.ActionUpdate, &project) || middleware.Has(c, policy.ProjectReadAny)
+	default:
+		var article models.Article
+		if err := db.First(&article, "id = ?", id).Error; err != nil {
+			utils.NotFound(c, "Artikel tidak ditemukan")
+			return resource, false, false
+		}
+		resource = services.TransferResource{Type: resourceType, ID: article.ID, OwnerID: article.UserID, Title: article.Title}
+		canView = middleware.Can(c, policy.ActionUpdate, &article) || middleware.Has(c, policy.ArticleReadAny)
+	}
+	return resource, canView, true
+}
+
+func toTransferResponses(transfers []models.OwnershipTransfer) []models.OwnershipTransferResponse {
+	titles := services.TransferTitles(transfers)
+	responses := make([]models.OwnershipTransferResponse, len(transfers))
+	for i := range transfers {
+		responses[i] = transfers[i].ToResponse(titles[transfers[i].ResourceID])
+	}
+	return responses
+}
+
+// RequestProject godoc
+// @Summary      Transfer project ownership
+// @Description  Ask another user to take over the project (project author only). Admins can set force to transfer it at once. Past sales keep their seller; new sales go to the new owner.
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        request body services.RequestTransferInput true "Recipient"
+// @Success      201 {object} map[string]interface{} "Transfer request"
+// @Failure      400 {object} map[string]interface{} "Invalid input or open request exists"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/transfer [post]
+func (h *OwnershipTransferHandler) RequestProject(c *gin.Context) {
+	h.request(c, models.TransferResourceProject)
+}
+
+// RequestArticle godoc
+// @Summary      Transfer article ownership
+// @Description  Ask another user to take over the article (author only). Admins can set force to transfer it at once.
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Article ID" format(uuid)
+// @Param        request body services.RequestTransferInput true "Recipient"
+// @Success      201 {object} map[string]interface{} "Transfer request"
+// @Failure      400 {object} map[string]interface{} "Invalid input or open request exists"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Article not found"
+// @Router       /articles/{id}/transfer [post]
+func (h *OwnershipTransferHandler) RequestArticle(c *gin.Context) {
+	h.request(c, models.TransferResourceArticle)
+}
+
+func (h *OwnershipTransferHandler) request(c *gin.Context, resourceType models.TransferResourceType) {
+	resource, _, ok := loadTransferResource(c, resourceType)
+	if !ok {
+		return
+	}
+
+	var input services.RequestTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	// Only the author hands over a resource; admins may force a transfer of any resource
+	currentUser := middleware.GetCurrentUser(c)
+	if input.Force {
+		if !middleware.Has(c, policy.OwnershipTransferForce) {
+			utils.Forbidden(c, "Tidak diizinkan memaksa pemindahan kepemilikan")
+			return
+		}
+	} else if resource.OwnerID != currentUser.ID {
+		utils.Forbidden(c, "Hanya pemilik yang dapat memindahkan kepemilikan")
+		return
+	}
+
+	transfer, err := services.RequestOwnershipTransfer(resource, currentUser.ID, &input, clientInfo(c))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Created(c, transfer.ToResponse(resource.Title))
+}
+
+// ProjectHistory godoc
+// @Summary      Project ownership history
+// @Description  Get every ownership transfer of the project, newest first (editors, admin or moderator)
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Transfers"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/transfers [get]
+func (h *OwnershipTransferHandler) ProjectHistory(c *gin.Context) {
+	h.history(c, models.TransferResourceProject)
+}
+
+// ArticleHistory godoc
+// @Summary      Article ownership history
+// @Description  Get every ownership transfer of the article, newest first (author, admin or moderator)
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Article ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Transfers"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Article not found"
+// @Router       /articles/{id}/transfers [get]
+func (h *OwnershipTransferHandler) ArticleHistory(c *gin.Context) {
+	h.history(c, models.TransferResourceArticle)
+}
+
+func (h *OwnershipTransferHandler) history(c *gin.Context, resourceType models.TransferResourceType) {
+	resource, canView, ok := loadTransferResource(c, resourceType)
+	if !ok {
+		return
+	}
+	if !canView {
+		utils.Forbidden(c, "Tidak diizinkan melihat riwayat kepemilikan")
+		return
+	}
+
+	transfers, err := services.ListResourceTransfers(resource.Type, resource.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat riwayat kepemilikan")
+		return
+	}
+
+	utils.Success(c, toTransferResponses(transfers))
+}
+
+// List godoc
+// @Summary      List my transfers
+// @Description  Get the ownership transfers the current user sent, received or requested, newest first
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Transfers"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /transfers [get]
+func (h *OwnershipTransferHandler) List(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+
+	transfers, err := services.ListOwnershipTransfers(currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat pemindahan kepemilikan")
+		return
+	}
+
+	utils.Success(c, toTransferResponses(transfers))
+}
+
+// Accept godoc
+// @Summary      Accept transfer
+// @Description  Become the owner of the project or article (recipient only)
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Transfer ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Transfer accepted"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or resource changed hands"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /transfers/{id}/accept [post]
+func (h *OwnershipTransferHandler) Accept(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.AcceptOwnershipTransfer(id, currentUser.ID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Kepemilikan berhasil dipindahkan", nil)
+}
+
+// Decline godoc
+// @Summary      Decline transfer
+// @Description  Turn down an ownership transfer (recipient only)
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Transfer ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Transfer declined"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Transfer not found"
+// @Router       /transfers/{id}/decline [post]
+func (h *OwnershipTransferHandler) Decline(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.DeclineOwnershipTransfer(id, currentUser.ID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Permintaan pemindahan ditolak", nil)
+}
+
+// Cancel godoc
+// @Summary      Cancel transfer
+// @Description  Withdraw an open ownership transfer (owner or requester only)
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Transfer ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Transfer cancelled"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      404 {object} map[string]interface{} "Transfer not found"
+// @Router       /transfers/{id}/cancel [post]
+func (h *OwnershipTransferHandler) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.CancelOwnershipTransfer(id, currentUser.ID); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Permintaan pemindahan dibatalkan", nil)
+}
@@ -2,15 +2,20 @@ package handlers
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/middleware"
 	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
 	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProjectHandler struct{}
@@ -27,11 +32,14 @@ func NewProjectHandler() *ProjectHandler {
 // @Produce      json
 // @Param        page query int false "Page number" default(1)
 // @Param        perPage query int false "Items per page" default(12)
-// @Param        search query string false "Search by title or description"
+// @Param        search query string false "Full-text search in title, description, tech stack and author name; results are ordered by relevance"
 // @Param        type query string false "Filter by type (free, paid)"
 // @Param        categoryId query string false "Filter by category ID"
-// @Param        userId query string false "Filter by user ID"
-// @Param        status query string false "Filter by status (published, draft, blocked)" default(published)
+// @Param        technologies query string false "Comma-separated technology slugs or names; only projects using all of them"
+// @Param        userId query string false "Filter by user ID, including projects the user collaborates on"
+// @Param        verifiedStudents query bool false "Only projects by verified students"
+// @Param        universityId query string false "Only projects by verified students of this university" format(uuid)
+// @Param        status query string false "Filter by status (published, draft, pending_review, rejected, scheduled, blocked). Drafts are only listed for their author." default(published)
 // @Success      200 {object} map[string]interface{} "Paginated projects list"
 // @Router       /projects [get]
 func (h *ProjectHandler) List(c *gin.Context) {
@@ -44,6 +52,9 @@ func (h *ProjectHandler) List(c *gin.Context) {
 	categoryID := c.Query("categoryId")
 	userID := c.Query("userId")
 	status := c.DefaultQuery("status", "published")
+	verifiedStudents := c.Query("verifiedStudents") == "true"
+	universityID := c.Query("universityId")
+	technologies := c.Query("technologies")
 
 	if page < 1 {
 		page = 1
@@ -52,18 +63,37 @@ func (h *ProjectHandler) List(c *gin.Context) {
 		perPage = 12
 	}
 
-	query := db.Model(&models.Project{}).Preload("User").Preload("Images")
+	query := db.Model(&models.Project{}).Preload("User.VerifiedUniversity").Preload("Images").Preload("Technologies").Scopes(services.WithCollaborators)
 
-	// Only show published projects to non-admins
+	// Published projects are public. Drafts are only listed for their author; other
+	// hidden states for their author and users who can see hidden projects.
 	currentUser := middleware.GetCurrentUser(c)
-	if currentUser == nil || currentUser.Role == models.RoleUser {
+	switch models.ProjectStatus(status) {
+	case models.ProjectStatusPublished:
 		query = query.Where("status = ?", models.ProjectStatusPublished)
-	} else if status != "" {
+	case models.ProjectStatusDraft, models.ProjectStatusPendingReview, models.ProjectStatusRejected,
+		models.ProjectStatusScheduled, models.ProjectStatusBlocked:
+		if currentUser == nil {
+			utils.Unauthorized(c, "Login diperlukan untuk melihat project yang belum terbit")
+			return
+		}
 		query = query.Where("status = ?", status)
+		if status == string(models.ProjectStatusDraft) || !middleware.Has(c, policy.ProjectReadAny) {
+			query = query.Where("user_id = ?", currentUser.ID)
+		}
+	case "":
+		if !middleware.Has(c, policy.ProjectReadAny) {
+			query = query.Where("status = ?", models.ProjectStatusPublished)
+		} else {
+			query = query.Where("status <> ? OR user_id = ?", models.ProjectStatusDraft, currentUser.ID)
+		}
+	default:
+		utils.BadRequest(c, "Status project tidak valid")
+		return
 	}
 
 	if search != "" {
-		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Scopes(services.SearchScope("projects", search))
 	}
 	if projectType != "" {
 		query = query.Where("type = ?", projectType)
@@ -71,14 +101,41 @@ func (h *ProjectHandler) List(c *gin.Context) {
 	if categoryID != "" {
 		query = query.Where("category_id = ?", categoryID)
 	}
+	// A member's projects include the ones they collaborate on
 	if userID != "" {
-		query = query.Where("user_id = ?", userID)
+		query = query.Where("user_id = ? OR id IN (?)", userID, db.Model(&models.ProjectCollaborator{}).Select("project_id").
+			Where("user_id = ? AND status = ?", userID, models.CollaboratorAccepted))
+	}
+	if verifiedStudents {
+		query = query.Where("user_id IN (?)", db.Model(&models.User{}).Select("id").Where("student_verified_at IS NOT NULL"))
+	}
+	if universityID != "" {
+		query = query.Where("user_id IN (?)", db.Model(&models.User{}).Select("id").
+			Where("verified_university_id = ? AND student_verified_at IS NOT NULL", universityID))
+	}
+	if technologies != "" {
+		// No project uses a technology that does not exist
+		technologyIDs, ok := services.FindTechnologyIDs(strings.Split(technologies, ","))
+		if !ok {
+			utils.Paginated(c, []models.ProjectResponse{}, 0, page, perPage)
+			return
+		}
+		if len(technologyIDs) > 0 {
+			query = query.Where("id IN (?)", db.Table("project_technologies").Select("project_id").
+				Where("technology_id IN ?", technologyIDs).
+				Group("project_id").
+				Having("COUNT(*) = ?", len(technologyIDs)))
+		}
 	}
 
 	var total int64
 	query.Count(&total)
 
 	var projects []models.Project
+	// The most relevant matches come first
+	if search != "" {
+		query = query.Order(services.SearchRankOrder)
+	}
 	query.Offset((page - 1) * perPage).Limit(perPage).Order("created_at DESC").Find(&projects)
 
 	responses := make([]models.ProjectResponse, len(projects))
@@ -92,55 +149,95 @@ func (h *ProjectHandler) List(c *gin.Context) {
 }
 
 // Get godoc
-// @Summary      Get project by ID
-// @Description  Get project details by project ID
+// @Summary      Get project by ID or slug
+// @Description  Get project details by project ID, or by the author's username and the project slug. Previous usernames and slugs answer with 301 to the current URL.
 // @Tags         projects
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "Project ID" format(uuid)
+// @Param        username path string true "Author username"
+// @Param        slug path string true "Project slug"
 // @Success      200 {object} map[string]interface{} "Project details"
+// @Success      301 {object} map[string]interface{} "Username or slug changed, see Location"
 // @Failure      400 {object} map[string]interface{} "Invalid ID"
 // @Failure      404 {object} map[string]interface{} "Project not found"
 // @Router       /projects/{id} [get]
+// @Router       /u/{username}/{slug} [get]
 func (h *ProjectHandler) Get(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		utils.BadRequest(c, "ID tidak valid")
+	id, moved, ok := resolveProjectID(c)
+	if !ok {
 		return
 	}
 
 	db := database.GetDB()
 	var project models.Project
-	if err := db.Preload("User").Preload("Images").Preload("Category").First(&project, "id = ?", id).Error; err != nil {
+	if err := db.Preload("User.VerifiedUniversity").Preload("Images").Preload("Category").Preload("Technologies").Scopes(services.WithCollaborators).First(&project, "id = ?", id).Error; err != nil {
 		utils.NotFound(c, "Project tidak ditemukan")
 		return
 	}
 
-	// Check if blocked
-	currentUser := middleware.GetCurrentUser(c)
-	if project.Status == models.ProjectStatusBlocked && (currentUser == nil || currentUser.Role == models.RoleUser) {
+	// Blocked projects are only visible to moderators, other unpublished ones also to their author
+	if project.Status == models.ProjectStatusBlocked && !middleware.Has(c, policy.ProjectReadAny) {
+		utils.NotFound(c, "Project tidak ditemukan")
+		return
+	}
+	if !project.IsPublished() && !middleware.Can(c, policy.ActionRead, &project) {
 		utils.NotFound(c, "Project tidak ditemukan")
 		return
 	}
 
+	if moved {
+		utils.MovedPermanently(c, "/api/v1/u/"+project.User.Username+"/"+project.Slug)
+		return
+	}
+
 	var commentCount int64
 	db.Model(&models.Comment{}).Where("project_id = ?", project.ID).Count(&commentCount)
 
 	utils.Success(c, project.ToResponse(int(commentCount)))
 }
 
-// CreateProjectInput for project creation
+// resolveProjectID reads the project from /projects/:id or /u/:username/:slug. moved is
+// true when an old username or slug was used. On failure the response is already written.
+func resolveProjectID(c *gin.Context) (id uuid.UUID, moved bool, ok bool) {
+	username := c.Param("username")
+	if username == "" {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			utils.BadRequest(c, "ID tidak valid")
+			return uuid.Nil, false, false
+		}
+		return id, false, true
+	}
+
+	author, userMoved, err := services.ResolveUsername(username)
+	if err != nil {
+		utils.NotFound(c, "Project tidak ditemukan")
+		return uuid.Nil, false, false
+	}
+	id, slugMoved, err := services.ResolveProjectSlug(author.ID, c.Param("slug"))
+	if err != nil {
+		utils.NotFound(c, "Project tidak ditemukan")
+		return uuid.Nil, false, false
+	}
+	return id, userMoved || slugMoved, true
+}
+
+// CreateProjectInput for project creation. Status is draft, pending_review or published;
+// publishing with a future publishAt schedules the project.
 type CreateProjectInput struct {
-	Title        string   `json:"title" validate:"required,min=3,max=255"`
-	Description  string   `json:"description" validate:"required"`
-	ThumbnailURL string   `json:"thumbnailUrl"`
-	Images       []string `json:"images"`
-	TechStack    []string `json:"techStack"`
-	GithubURL    string   `json:"githubUrl" validate:"omitempty,url"`
-	DemoURL      string   `json:"demoUrl" validate:"omitempty,url"`
-	Type         string   `json:"type" validate:"required,oneof=free paid"`
-	Price        int      `json:"price" validate:"omitempty,min=0"`
-	CategoryID   string   `json:"categoryId"`
+	Title        string     `json:"title" validate:"required,min=3,max=255"`
+	Description  string     `json:"description" validate:"required"`
+	ThumbnailURL string     `json:"thumbnailUrl"`
+	Images       []string   `json:"images"`
+	TechStack    []string   `json:"techStack"`
+	GithubURL    string     `json:"githubUrl" validate:"omitempty,url"`
+	DemoURL      string     `json:"demoUrl" validate:"omitempty,url"`
+	Type         string     `json:"type" validate:"required,oneof=free paid"`
+	Price        int        `json:"price" validate:"omitempty,min=0"`
+	CategoryID   string     `json:"categoryId"`
+	Status       string     `json:"status" validate:"omitempty,oneof=draft pending_review published"`
+	PublishAt    *time.Time `json:"publishAt"`
 }
 
 // Create godoc
@@ -182,7 +279,8 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 		DemoURL:      &input.DemoURL,
 		Type:         models.ProjectType(input.Type),
 		Price:        input.Price,
-		Status:       models.ProjectStatusPublished,
+		Status:       models.ProjectStatusDraft,
+		PublishAt:    input.PublishAt,
 	}
 
 	if input.CategoryID != "" {
@@ -190,26 +288,41 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 		project.CategoryID = &catID
 	}
 
-	if err := db.Create(&project).Error; err != nil {
-		utils.InternalServerError(c, "Gagal membuat project")
+	// Projects are published right away unless the author asks otherwise
+	status := models.ProjectStatusPublished
+	if input.Status != "" {
+		status = models.ProjectStatus(input.Status)
+	}
+	if err := services.ApplyProjectStatus(&project, status); err != nil {
+		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	// Create project images
-	for i, imgURL := range input.Images {
-		img := models.ProjectImage{
-			ProjectID: project.ID,
-			ImageURL:  imgURL,
-			SortOrder: i,
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		if err := services.SetProjectTechnologies(tx, &project, input.TechStack); err != nil {
+			return err
+		}
+		if err := services.ReplaceProjectImages(tx, &project, input.Images); err != nil {
+			return err
+		}
+		if err := services.AddProjectOwner(tx, &project); err != nil {
+			return err
 		}
-		db.Create(&img)
+		return services.RecordProjectRevision(tx, &project, nil, currentUser.ID, nil)
+	})
+	if err != nil {
+		utils.InternalServerError(c, "Gagal membuat project")
+		return
 	}
 
 	// Add EXP for creating project
 	services.AddUserExp(currentUser.ID, services.ExpCreateProject)
 
 	// Reload with relations
-	db.Preload("User").Preload("Images").First(&project, "id = ?", project.ID)
+	db.Preload("User.VerifiedUniversity").Preload("Images").Preload("Technologies").Scopes(services.WithCollaborators).First(&project, "id = ?", project.ID)
 
 	utils.Created(c, project.ToResponse(0))
 }
@@ -240,13 +353,12 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 	db := database.GetDB()
 
 	var project models.Project
-	if err := db.First(&project, "id = ?", id).Error; err != nil {
+	if err := db.Preload("Images", services.PreloadImagesInOrder).Scopes(services.WithCollaborators).First(&project, "id = ?", id).Error; err != nil {
 		utils.NotFound(c, "Project tidak ditemukan")
 		return
 	}
 
-	// Only owner or admin can update
-	if project.UserID != currentUser.ID && currentUser.Role != models.RoleAdmin {
+	if !middleware.Can(c, policy.ActionUpdate, &project) {
 		utils.Forbidden(c, "Tidak diizinkan mengubah project ini")
 		return
 	}
@@ -257,6 +369,15 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	// The state before the edit becomes the first revision of projects without history
+	before := project.Snapshot()
+	titleChanged := input.Title != project.Title
 	project.Title = input.Title
 	project.Description = &input.Description
 	project.ThumbnailURL = &input.ThumbnailURL
@@ -271,20 +392,44 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		project.CategoryID = &catID
 	}
 
-	db.Save(&project)
+	// Without a status the project keeps its current one, except that a scheduled
+	// project is checked again against the new publishAt
+	project.PublishAt = input.PublishAt
+	status := models.ProjectStatus(input.Status)
+	if status == "" && project.Status == models.ProjectStatusScheduled {
+		status = models.ProjectStatusPublished
+	}
+	if status != "" {
+		if err := services.ApplyProjectStatus(&project, status); err != nil {
+			utils.BadRequest(c, err.Error())
+			return
+		}
+	}
 
-	// Update images
-	db.Delete(&models.ProjectImage{}, "project_id = ?", id)
-	for i, imgURL := range input.Images {
-		img := models.ProjectImage{
-			ProjectID: project.ID,
-			ImageURL:  imgURL,
-			SortOrder: i,
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// A new title gets a new slug; the old one keeps redirecting
+		if titleChanged {
+			if err := services.RefreshProjectSlug(tx, &project); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit(clause.Associations).Save(&project).Error; err != nil {
+			return err
 		}
-		db.Create(&img)
+		if err := services.SetProjectTechnologies(tx, &project, input.TechStack); err != nil {
+			return err
+		}
+		if err := services.ReplaceProjectImages(tx, &project, input.Images); err != nil {
+			return err
+		}
+		return services.RecordProjectRevision(tx, &project, &before, currentUser.ID, nil)
+	})
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memperbarui project")
+		return
 	}
 
-	db.Preload("User").Preload("Images").First(&project, "id = ?", project.ID)
+	db.Preload("User.VerifiedUniversity").Preload("Images").Preload("Technologies").Scopes(services.WithCollaborators).First(&project, "id = ?", project.ID)
 
 	var commentCount int64
 	db.Model(&models.Comment{}).Where("project_id = ?", project.ID).Count(&commentCount)
@@ -305,6 +450,7 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 // @Failure      401 {object} map[string]interface{} "Unauthorized"
 // @Failure      403 {object} map[string]interface{} "Forbidden"
 // @Failure      404 {object} map[string]interface{} "Project not found"
+// @Failure      409 {object} map[string]interface{} "Project has been bought"
 // @Router       /projects/{id} [delete]
 func (h *ProjectHandler) Delete(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -313,22 +459,44 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	currentUser := middleware.GetCurrentUser(c)
 	db := database.GetDB()
 
 	var project models.Project
-	if err := db.First(&project, "id = ?", id).Error; err != nil {
+	if err := db.Scopes(services.WithCollaborators).First(&project, "id = ?", id).Error; err != nil {
 		utils.NotFound(c, "Project tidak ditemukan")
 		return
 	}
 
-	// Only owner or admin can delete
-	if project.UserID != currentUser.ID && currentUser.Role != models.RoleAdmin {
+	if !middleware.Can(c, policy.ActionDelete, &project) {
 		utils.Forbidden(c, "Tidak diizinkan menghapus project ini")
 		return
 	}
 
-	db.Delete(&project)
+	// Sold projects stay so that buyers keep their purchase and its release files. Pending
+	// payments may still succeed, so they block the deletion too.
+	var payments int64
+	db.Model(&models.Transaction{}).
+		Where("project_id = ? AND status IN ?", project.ID, []models.TransactionStatus{models.TransactionStatusSuccess, models.TransactionStatusPending}).
+		Count(&payments)
+	if payments > 0 {
+		utils.Conflict(c, "Project yang sudah dibeli tidak dapat dihapus, ubah statusnya menjadi draft untuk menyembunyikannya")
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Failed payments have nothing to keep
+		if err := tx.Where("project_id = ?", project.ID).Delete(&models.Transaction{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&project).Error
+	})
+	if err != nil {
+		utils.InternalServerError(c, "Gagal menghapus project")
+		return
+	}
+
+	// Release files are only removed once the project is gone
+	services.RemoveReleaseFiles(project.ID)
 	utils.SuccessWithMessage(c, "Project berhasil dihapus", nil)
 }
 
@@ -356,7 +524,7 @@ func (h *ProjectHandler) Like(c *gin.Context) {
 	db := database.GetDB()
 
 	var project models.Project
-	if err := db.First(&project, "id = ?", id).Error; err != nil {
+	if err := db.First(&project, "id = ?", id).Error; err != nil || !project.IsPublished() {
 		utils.NotFound(c, "Project tidak ditemukan")
 		return
 	}
@@ -405,11 +573,12 @@ func (h *ProjectHandler) View(c *gin.Context) {
 	}
 
 	db := database.GetDB()
-	db.Model(&models.Project{}).Where("id = ?", id).Update("views", db.Raw("views + 1"))
+	result := db.Model(&models.Project{}).Where("id = ? AND status = ?", id, models.ProjectStatusPublished).
+		Update("views", db.Raw("views + 1"))
 
 	// Add EXP to owner (could add daily cap logic here)
 	var project models.Project
-	if err := db.First(&project, "id = ?", id).Error; err == nil {
+	if result.RowsAffected > 0 && db.First(&project, "id = ?", id).Error == nil {
 		services.AddUserExp(project.UserID, services.ExpProjectViewed)
 	}
 
@@ -503,3 +672,81 @@ func (h *ProjectHandler) Unblock(c *gin.Context) {
 
 	utils.SuccessWithMessage(c, "Project berhasil dibuka blokirnya", nil)
 }
+
+// ReviewProjectInput carries the moderator's reason when rejecting a project
+type ReviewProjectInput struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+// Approve godoc
+// @Summary      Approve project
+// @Description  Publish a project waiting for review, or schedule it when the author set a later publishAt (moderator/admin only)
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Approved project"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or project not pending review"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /projects/{id}/approve [post]
+func (h *ProjectHandler) Approve(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	project, err := services.ApproveProject(id, currentUser.ID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Project berhasil disetujui", project.ToResponse(0))
+}
+
+// Reject godoc
+// @Summary      Reject project
+// @Description  Send a project waiting for review back to its author with a reason (moderator/admin only)
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        request body ReviewProjectInput true "Rejection reason"
+// @Success      200 {object} map[string]interface{} "Rejected project"
+// @Failure      400 {object} map[string]interface{} "Invalid input or project not pending review"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /projects/{id}/reject [post]
+func (h *ProjectHandler) Reject(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	var input ReviewProjectInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	project, err := services.RejectProject(id, currentUser.ID, input.Reason)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Project dikembalikan ke pembuatnya", project.ToResponse(0))
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ProjectReleaseHandler struct{}
+
+func NewProjectReleaseHandler() *ProjectReleaseHandler {
+	return &ProjectReleaseHandler{}
+}
+
+// loadRelease reads the release from the :rid parameter. On failure the response is already written.
+func loadRelease(c *gin.Context, project *models.Project) (*models.ProjectRelease, bool) {
+	releaseID, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		utils.BadRequest(c, "ID rilis tidak valid")
+		return nil, false
+	}
+
+	release, err := services.GetRelease(project.ID, releaseID)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return nil, false
+	}
+	return release, true
+}
+
+// List godoc
+// @Summary      List project releases
+// @Description  Get the releases of a project with their files and download counts, newest first. canDownload tells whether the current user may download them.
+// @Tags         releases
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Project ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Releases"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/releases [get]
+func (h *ProjectReleaseHandler) List(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if !project.IsPublished() && !middleware.Can(c, policy.ActionRead, project) {
+		utils.NotFound(c, "Project tidak ditemukan")
+		return
+	}
+
+	releases, err := services.ListReleases(project.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat rilis")
+		return
+	}
+
+	responses := make([]models.ProjectReleaseResponse, len(releases))
+	for i := range releases {
+		responses[i] = releases[i].ToResponse()
+	}
+
+	utils.Success(c, gin.H{
+		"releases":    responses,
+		"canDownload": services.CanDownloadRelease(project, currentUser),
+	})
+}
+
+// Create godoc
+// @Summary      Create project release
+// @Description  Upload a new release with one or more ZIP or PDF files. The files are stored privately (owner or maintainer only).
+// @Tags         releases
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        version formData string true "Version, unique per project"
+// @Param        notes formData string false "Release notes"
+// @Param        files formData file true "ZIP or PDF files"
+// @Success      201 {object} map[string]interface{} "Created release"
+// @Failure      400 {object} map[string]interface{} "Invalid input or file"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Failure      500 {object} map[string]interface{} "Release could not be stored"
+// @Router       /projects/{id}/releases [post]
+func (h *ProjectReleaseHandler) Create(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if !middleware.Can(c, policy.ActionUpdate, project) {
+		utils.Forbidden(c, "Tidak diizinkan mengubah project ini")
+		return
+	}
+
+	var input services.CreateReleaseInput
+	if err := c.ShouldBind(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		utils.BadRequest(c, "File tidak ditemukan")
+		return
+	}
+
+	release, err := services.CreateRelease(project, currentUser.ID, &input, form.File["files"])
+	if errors.Is(err, services.ErrCreateReleaseFailed) {
+		utils.InternalServerError(c, "Gagal membuat rilis")
+		return
+	}
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Created(c, release.ToResponse())
+}
+
+// Delete godoc
+// @Summary      Delete project release
+// @Description  Delete a release and its files (owner or maintainer only)
+// @Tags         releases
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        rid path string true "Release ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Release deleted"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Release not found"
+// @Router       /projects/{id}/releases/{rid} [delete]
+func (h *ProjectReleaseHandler) Delete(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	if !middleware.Can(c, policy.ActionUpdate, project) {
+		utils.Forbidden(c, "Tidak diizinkan mengubah project ini")
+		return
+	}
+
+	release, ok := loadRelease(c, project)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteRelease(release); err != nil {
+		utils.InternalServerError(c, "Gagal menghapus rilis")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Rilis berhasil dihapus", nil)
+}
+
+// Download godoc
+// @Summary      Get release download URL
+// @Description  Issue a short-lived signed URL for a release file (team members and buyers only). Each URL works once; request a new one to download again. assetId may be left out when the release has a single file.
+// @Tags         releases
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        rid path string true "Release ID" format(uuid)
+// @Param        assetId query string false "Release file ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Signed URL and its expiry"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Not purchased"
+// @Failure      404 {object} map[string]interface{} "Release not found"
+// @Router       /projects/{id}/releases/{rid}/download [get]
+func (h *ProjectReleaseHandler) Download(c *gin.Context) {
+	project, ok := loadTeamProject(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if !services.CanDownloadRelease(project, currentUser) {
+		utils.Forbidden(c, "Beli project ini untuk mengunduh rilisnya")
+		return
+	}
+
+	release, ok := loadRelease(c, project)
+	if !ok {
+		return
+	}
+
+	var asset *models.ReleaseAsset
+	if value := c.Query("assetId"); value != "" {
+		assetID, err := uuid.Parse(value)
+		if err != nil {
+			utils.BadRequest(c, "ID file tidak valid")
+			return
+		}
+		for i := range release.Assets {
+			if release.Assets[i].ID == assetID {
+				asset = &release.Assets[i]
+			}
+		}
+	} else if len(release.Assets) == 1 {
+		asset = &release.Assets[0]
+	} else {
+		utils.BadRequest(c, "Pilih file yang akan diunduh dengan assetId")
+		return
+	}
+	if asset == nil {
+		utils.NotFound(c, "File tidak ditemukan")
+		return
+	}
+
+	url, expiresAt, err := services.IssueDownloadURL(asset, currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal membuat tautan unduhan")
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"url":       url,
+		"fileName":  asset.FileName,
+		"expiresAt": expiresAt,
+	})
+}
+
+// Serve godoc
+// @Summary      Download release file
+// @Description  Download a release file with a signed URL issued by the download endpoint. Each URL can be used once and every use is counted.
+// @Tags         releases
+// @Produce      octet-stream
+// @Param        token path string true "Signed download token"
+// @Success      200 {file} file "Release file"
+// @Failure      403 {object} map[string]interface{} "Invalid, expired or already used link"
+// @Router       /downloads/{token} [get]
+func (h *ProjectReleaseHandler) Serve(c *gin.Context) {
+	asset, path, err := services.OpenDownload(c.Param("token"))
+	if err != nil {
+		utils.Forbidden(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", asset.ContentType)
+	c.FileAttachment(path, asset.FileName)
+}
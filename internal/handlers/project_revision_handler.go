@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ProjectRevisionHandler struct{}
+
+func NewProjectRevisionHandler() *ProjectRevisionHandler {
+	return &ProjectRevisionHandler{}
+}
+
+// loadRevisionProject reads the project from the :id parameter and checks that the current
+// user may see its history: editors of the project and users who can see hidden projects.
+// On failure the response is already written.
+func loadRevisionProject(c *gin.Context) (*models.Project, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return nil, false
+	}
+
+	var project models.Project
+	if err := database.GetDB().Scopes(services.WithCollaborators).First(&project, "id = ?", id).Error; err != nil {
+		utils.NotFound(c, "Project tidak ditemukan")
+		return nil, false
+	}
+
+	if !middleware.Can(c, policy.ActionUpdate, &project) && !middleware.Has(c, policy.ProjectReadAny) {
+		utils.Forbidden(c, "Tidak diizinkan melihat riwayat project ini")
+		return nil, false
+	}
+	return &project, true
+}
+
+func parseRevisionNumber(c *gin.Context, value string) (int, bool) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		utils.BadRequest(c, "Nomor revisi tidak valid")
+		return 0, false
+	}
+	return number, true
+}
+
+// List godoc
+// @Summary      List project revisions
+// @Description  Get the revisions of a project, newest first (owner, admin or moderator)
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Revisions with editor and changed fields"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Project not found"
+// @Router       /projects/{id}/revisions [get]
+func (h *ProjectRevisionHandler) List(c *gin.Context) {
+	project, ok := loadRevisionProject(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := services.ListProjectRevisions(project.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat riwayat project")
+		return
+	}
+
+	responses := make([]models.ProjectRevisionResponse, len(revisions))
+	for i := range revisions {
+		responses[i] = revisions[i].ToResponse(false)
+	}
+
+	utils.Success(c, responses)
+}
+
+// Get godoc
+// @Summary      Get project revision
+// @Description  Get one revision of a project including its full snapshot (owner, admin or moderator)
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        rev path int true "Revision number"
+// @Success      200 {object} map[string]interface{} "Revision with snapshot"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or revision number"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Revision not found"
+// @Router       /projects/{id}/revisions/{rev} [get]
+func (h *ProjectRevisionHandler) Get(c *gin.Context) {
+	project, ok := loadRevisionProject(c)
+	if !ok {
+		return
+	}
+
+	number, ok := parseRevisionNumber(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	revision, err := services.GetProjectRevision(project.ID, number)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	utils.Success(c, revision.ToResponse(true))
+}
+
+// Diff godoc
+// @Summary      Compare project revisions
+// @Description  Get the fields that differ between a revision and an earlier one, by default the revision right before it (owner, admin or moderator)
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        rev path int true "Revision number"
+// @Param        from query int false "Revision to compare against"
+// @Success      200 {object} map[string]interface{} "Field-level changes"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or revision number"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Revision not found"
+// @Router       /projects/{id}/revisions/{rev}/diff [get]
+func (h *ProjectRevisionHandler) Diff(c *gin.Context) {
+	project, ok := loadRevisionProject(c)
+	if !ok {
+		return
+	}
+
+	number, ok := parseRevisionNumber(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	revision, err := services.GetProjectRevision(project.ID, number)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	// The first revision is compared against an empty project
+	var from models.ProjectSnapshot
+	fromNumber := number - 1
+	if value := c.Query("from"); value != "" {
+		if fromNumber, ok = parseRevisionNumber(c, value); !ok {
+			return
+		}
+	}
+	if fromNumber > 0 {
+		previous, err := services.GetProjectRevision(project.ID, fromNumber)
+		if err != nil {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		from = previous.Snapshot
+	}
+
+	utils.Success(c, gin.H{
+		"from":    fromNumber,
+		"to":      number,
+		"changes": revision.Snapshot.Diff(from),
+	})
+}
+
+// Restore godoc
+// @Summary      Restore project revision
+// @Description  Put the project back to the state of a revision. The restore is recorded as a new revision (owner or admin only).
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Project ID" format(uuid)
+// @Param        rev path int true "Revision number"
+// @Success      200 {object} map[string]interface{} "Restored project"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or revision number"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Revision not found"
+// @Router       /projects/{id}/revisions/{rev}/restore [post]
+func (h *ProjectRevisionHandler) Restore(c *gin.Context) {
+	project, ok := loadRevisionProject(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if !middleware.Can(c, policy.ActionUpdate, project) {
+		utils.Forbidden(c, "Tidak diizinkan mengubah project ini")
+		return
+	}
+
+	number, ok := parseRevisionNumber(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	if _, err := services.GetProjectRevision(project.ID, number); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	restored, err := services.RestoreProjectRevision(project.ID, number, currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memulihkan revisi")
+		return
+	}
+
+	db := database.GetDB()
+	db.Preload("User.VerifiedUniversity").Preload("Images", services.PreloadImagesInOrder).Preload("Technologies").Scopes(services.WithCollaborators).First(restored, "id = ?", restored.ID)
+
+	var commentCount int64
+	db.Model(&models.Comment{}).Where("project_id = ?", restored.ID).Count(&commentCount)
+
+	utils.SuccessWithMessage(c, "Revisi berhasil dipulihkan", restored.ToResponse(int(commentCount)))
+}
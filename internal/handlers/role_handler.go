@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct{}
+
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{}
+}
+
+// Permissions godoc
+// @Summary      Role permission matrix
+// @Description  List every permission and the permissions granted to each role (admin only)
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Permissions and role matrix"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /roles/permissions [get]
+func (h *RoleHandler) Permissions(c *gin.Context) {
+	utils.Success(c, gin.H{
+		"permissions": policy.Permissions,
+		"roles":       policy.Matrix(),
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct{}
+
+func NewSearchHandler() *SearchHandler {
+	return &SearchHandler{}
+}
+
+// Search godoc
+// @Summary      Search projects and articles
+// @Description  Full-text search over published projects and articles, the most relevant first. Snippets are HTML-escaped with the matched words in <mark> tags. q accepts web search syntax: "quoted phrases", OR and -excluded words.
+// @Tags         search
+// @Accept       json
+// @Produce      json
+// @Param        q query string true "Search query"
+// @Param        type query string false "Only projects or only articles (project, article)"
+// @Param        page query int false "Page number" default(1)
+// @Param        perPage query int false "Items per page" default(10)
+// @Success      200 {object} map[string]interface{} "Paginated search results"
+// @Failure      400 {object} map[string]interface{} "Missing query or invalid type"
+// @Router       /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	resultType := c.Query("type")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("perPage", "10"))
+
+	if q == "" {
+		utils.BadRequest(c, "Kata kunci pencarian diperlukan")
+		return
+	}
+	if resultType != "" && resultType != services.SearchTypeProject && resultType != services.SearchTypeArticle {
+		utils.BadRequest(c, "Tipe pencarian tidak valid")
+		return
+	}
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 50 {
+		perPage = 10
+	}
+
+	results, total, err := services.Search(q, resultType, page, perPage)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal melakukan pencarian")
+		return
+	}
+
+	utils.Paginated(c, results, total, page, perPage)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TechnologyHandler struct{}
+
+func NewTechnologyHandler() *TechnologyHandler {
+	return &TechnologyHandler{}
+}
+
+// List godoc
+// @Summary      Autocomplete technologies
+// @Description  Get technologies whose name or alias starts with q, the most used first, with project counts
+// @Tags         technologies
+// @Accept       json
+// @Produce      json
+// @Param        q query string false "Name or alias prefix"
+// @Param        limit query int false "Maximum results" default(10)
+// @Success      200 {object} map[string]interface{} "Technologies list"
+// @Router       /technologies [get]
+func (h *TechnologyHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	technologies, err := services.SearchTechnologies(c.Query("q"), limit)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal memuat teknologi")
+		return
+	}
+
+	utils.Success(c, technologies)
+}
+
+// Create godoc
+// @Summary      Create technology
+// @Description  Add a canonical technology with its aliases (admin only)
+// @Tags         technologies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.TechnologyInput true "Technology data"
+// @Success      201 {object} map[string]interface{} "Created technology"
+// @Failure      400 {object} map[string]interface{} "Invalid input or name already used"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /technologies [post]
+func (h *TechnologyHandler) Create(c *gin.Context) {
+	var input services.TechnologyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	technology, err := services.CreateTechnology(&input)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Created(c, technology)
+}
+
+// Update godoc
+// @Summary      Update technology
+// @Description  Rename a technology or change its aliases, icon and color (admin only). The old name stays as an alias and project tech stacks follow the new name.
+// @Tags         technologies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Technology ID" format(uuid)
+// @Param        request body services.TechnologyInput true "Technology data"
+// @Success      200 {object} map[string]interface{} "Updated technology"
+// @Failure      400 {object} map[string]interface{} "Invalid input or name already used"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Technology not found"
+// @Router       /technologies/{id} [put]
+func (h *TechnologyHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	technology, err := services.GetTechnology(id)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	var input services.TechnologyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	if err := services.UpdateTechnology(technology, &input); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Success(c, technology)
+}
+
+// MergeTechnologyInput names the technology a duplicate is merged into
+type MergeTechnologyInput struct {
+	TargetID string `json:"targetId" validate:"required,uuid"`
+}
+
+// Merge godoc
+// @Summary      Merge duplicate technology
+// @Description  Merge a duplicate technology into another one (admin only). Its projects move to the target and its names become aliases of the target.
+// @Tags         technologies
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "Duplicate technology ID" format(uuid)
+// @Param        request body MergeTechnologyInput true "Target technology"
+// @Success      200 {object} map[string]interface{} "Target technology after the merge"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "Technology not found"
+// @Router       /technologies/{id}/merge [post]
+func (h *TechnologyHandler) Merge(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	var input MergeTechnologyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	source, err := services.GetTechnology(id)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+	target, err := services.GetTechnology(uuid.MustParse(input.TargetID))
+	if err != nil {
+		utils.NotFound(c, "Teknologi tujuan tidak ditemukan")
+		return
+	}
+
+	if err := services.MergeTechnologies(source, target); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	target, _ = services.GetTechnology(target.ID)
+	utils.Success(c, target)
+}
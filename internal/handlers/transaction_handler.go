@@ -122,15 +122,17 @@ func (h *TransactionHandler) List(c *gin.Context) {
 		perPage = 10
 	}
 
-	query := db.Model(&models.Transaction{}).Preload("Project").Preload("Buyer")
+	query := db.Model(&models.Transaction{}).Preload("Project").Preload("Buyer").Preload("Shares")
 
+	// Sales include every sale the user shares in as a co-owner
+	sales := db.Model(&models.TransactionShare{}).Select("transaction_id").Where("user_id = ?", currentUser.ID)
 	switch transactionType {
 	case "purchases":
 		query = query.Where("buyer_id = ?", currentUser.ID)
 	case "sales":
-		query = query.Where("seller_id = ?", currentUser.ID)
+		query = query.Where("seller_id = ? OR id IN (?)", currentUser.ID, sales)
 	default:
-		query = query.Where("buyer_id = ? OR seller_id = ?", currentUser.ID, currentUser.ID)
+		query = query.Where("buyer_id = ? OR seller_id = ? OR id IN (?)", currentUser.ID, currentUser.ID, sales)
 	}
 
 	var total int64
@@ -175,7 +177,7 @@ func (h *TransactionHandler) AdminList(c *gin.Context) {
 		perPage = 10
 	}
 
-	query := db.Model(&models.Transaction{}).Preload("Project").Preload("Buyer").Preload("Seller")
+	query := db.Model(&models.Transaction{}).Preload("Project").Preload("Buyer").Preload("Seller").Preload("Shares")
 
 	if status != "" {
 		query = query.Where("status = ?", status)
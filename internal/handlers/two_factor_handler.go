@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct{}
+
+func NewTwoFactorHandler() *TwoFactorHandler {
+	return &TwoFactorHandler{}
+}
+
+// bindTwoFactorCode binds and validates a 2FA code from the request body
+func bindTwoFactorCode(c *gin.Context) (*services.TwoFactorCodeInput, bool) {
+	var input services.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return nil, false
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return nil, false
+	}
+
+	return &input, true
+}
+
+// Verify godoc
+// @Summary      Complete 2FA login
+// @Description  Exchange the login challenge token and a TOTP or recovery code for a token pair
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body services.TwoFactorLoginInput true "Challenge token and code"
+// @Success      200 {object} map[string]interface{} "User and token pair"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Invalid code or expired challenge"
+// @Failure      429 {object} map[string]interface{} "Too many failed attempts"
+// @Router       /auth/2fa/verify [post]
+func (h *TwoFactorHandler) Verify(c *gin.Context) {
+	var input services.TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	result, err := services.VerifyTwoFactorLogin(&input, clientInfo(c))
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	respondAuthResult(c, result)
+}
+
+// Setup godoc
+// @Summary      Start 2FA enrollment
+// @Description  Generate a TOTP secret and provisioning URI to show as a QR code
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Secret and provisioning URI"
+// @Failure      400 {object} map[string]interface{} "2FA already enabled"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+
+	setup, err := services.SetupTwoFactor(currentUser.ID)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Success(c, setup)
+}
+
+// Enable godoc
+// @Summary      Enable 2FA
+// @Description  Confirm enrollment with a TOTP code and receive recovery codes (shown once)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.TwoFactorCodeInput true "TOTP code"
+// @Success      200 {object} map[string]interface{} "Recovery codes"
+// @Failure      400 {object} map[string]interface{} "Invalid code"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	input, ok := bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	codes, err := services.EnableTwoFactor(currentUser.ID, input.Code)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Autentikasi dua faktor berhasil diaktifkan", gin.H{
+		"recoveryCodes": codes,
+	})
+}
+
+// Disable godoc
+// @Summary      Disable 2FA
+// @Description  Turn off 2FA with a TOTP or recovery code (not allowed for roles that require 2FA)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.TwoFactorCodeInput true "TOTP or recovery code"
+// @Success      200 {object} map[string]interface{} "2FA disabled"
+// @Failure      400 {object} map[string]interface{} "Invalid code"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	input, ok := bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.DisableTwoFactor(currentUser.ID, input.Code); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Autentikasi dua faktor berhasil dinonaktifkan", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace all recovery codes after verifying a TOTP or recovery code
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.TwoFactorCodeInput true "TOTP or recovery code"
+// @Success      200 {object} map[string]interface{} "New recovery codes"
+// @Failure      400 {object} map[string]interface{} "Invalid code"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	input, ok := bindTwoFactorCode(c)
+	if !ok {
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	codes, err := services.RegenerateRecoveryCodes(currentUser.ID, input.Code)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.Success(c, gin.H{
+		"recoveryCodes": codes,
+	})
+}
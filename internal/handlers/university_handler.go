@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type UniversityHandler struct{}
+
+func NewUniversityHandler() *UniversityHandler {
+	return &UniversityHandler{}
+}
+
+// List godoc
+// @Summary      List universities
+// @Description  Get universities whose campus email domains are accepted for student verification
+// @Tags         universities
+// @Accept       json
+// @Produce      json
+// @Param        search query string false "Search by name"
+// @Success      200 {object} map[string]interface{} "Universities list"
+// @Router       /universities [get]
+func (h *UniversityHandler) List(c *gin.Context) {
+	db := database.GetDB()
+
+	query := db.Order("name ASC")
+	if search := c.Query("search"); search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+
+	var universities []models.University
+	query.Find(&universities)
+
+	utils.Success(c, universities)
+}
+
+// UniversityInput for university creation and updates
+type UniversityInput struct {
+	Name    string   `json:"name" validate:"required,min=2,max=255"`
+	Domains []string `json:"domains" validate:"required,min=1,dive,fqdn"`
+}
+
+func normalizeDomains(domains []string) pq.StringArray {
+	seen := map[string]bool{}
+	var result pq.StringArray
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if domain != "" && !seen[domain] {
+			seen[domain] = true
+			result = append(result, domain)
+		}
+	}
+	return result
+}
+
+// Create godoc
+// @Summary      Create university
+// @Description  Register a university and its campus email domains (admin only)
+// @Tags         universities
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body UniversityInput true "University data"
+// @Success      201 {object} map[string]interface{} "Created university"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /universities [post]
+func (h *UniversityHandler) Create(c *gin.Context) {
+	var input UniversityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	db := database.GetDB()
+
+	slug := generateSlug(input.Name)
+	var existing models.University
+	if err := db.Where("slug = ?", slug).First(&existing).Error; err == nil {
+		utils.BadRequest(c, "Universitas dengan nama serupa sudah ada")
+		return
+	}
+
+	domains := normalizeDomains(input.Domains)
+	if err := db.Where("domains && ?", domains).First(&existing).Error; err == nil {
+		utils.BadRequest(c, "Domain sudah digunakan oleh "+existing.Name)
+		return
+	}
+
+	university := models.University{
+		Name:    input.Name,
+		Slug:    slug,
+		Domains: domains,
+	}
+
+	if err := db.Create(&university).Error; err != nil {
+		utils.InternalServerError(c, "Gagal membuat universitas")
+		return
+	}
+
+	utils.Created(c, university)
+}
+
+// Update godoc
+// @Summary      Update university
+// @Description  Update a university and its campus email domains (admin only)
+// @Tags         universities
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "University ID" format(uuid)
+// @Param        request body UniversityInput true "University data"
+// @Success      200 {object} map[string]interface{} "Updated university"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "University not found"
+// @Router       /universities/{id} [put]
+func (h *UniversityHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	db := database.GetDB()
+
+	var university models.University
+	if err := db.First(&university, "id = ?", id).Error; err != nil {
+		utils.NotFound(c, "Universitas tidak ditemukan")
+		return
+	}
+
+	var input UniversityInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(400, gin.H{"success": false, "errors": errors})
+		return
+	}
+
+	newSlug := generateSlug(input.Name)
+	var existing models.University
+	if newSlug != university.Slug {
+		if err := db.Where("slug = ? AND id != ?", newSlug, id).First(&existing).Error; err == nil {
+			utils.BadRequest(c, "Universitas dengan nama serupa sudah ada")
+			return
+		}
+		university.Slug = newSlug
+	}
+
+	domains := normalizeDomains(input.Domains)
+	if err := db.Where("domains && ? AND id != ?", domains, id).First(&existing).Error; err == nil {
+		utils.BadRequest(c, "Domain sudah digunakan oleh "+existing.Name)
+		return
+	}
+
+	university.Name = input.Name
+	university.Domains = domains
+
+	db.Save(&university)
+
+	utils.Success(c, university)
+}
+
+// Delete godoc
+// @Summary      Delete university
+// @Description  Delete a university (admin only). Students verified with it lose their verified status.
+// @Tags         universities
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "University ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "University deleted"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /universities/{id} [delete]
+func (h *UniversityHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	db := database.GetDB()
+
+	db.Model(&models.User{}).Where("verified_university_id = ?", id).Updates(map[string]interface{}{
+		"campus_email":           nil,
+		"verified_university_id": nil,
+		"student_verified_at":    nil,
+	})
+
+	if err := db.Delete(&models.University{}, "id = ?", id).Error; err != nil {
+		utils.InternalServerError(c, "Gagal menghapus universitas")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Universitas berhasil dihapus", nil)
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"log"
+	"net/http"
 	"strconv"
 
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/middleware"
 	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
 	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -50,7 +53,7 @@ func (h *UserHandler) List(c *gin.Context) {
 		perPage = 10
 	}
 
-	query := db.Model(&models.User{})
+	query := db.Model(&models.User{}).Preload("VerifiedUniversity")
 
 	if search != "" {
 		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
@@ -77,33 +80,57 @@ func (h *UserHandler) List(c *gin.Context) {
 }
 
 // Get godoc
-// @Summary      Get user by ID
-// @Description  Get user profile by user ID
+// @Summary      Get user by ID or username
+// @Description  Get user profile by user ID or username. Email, phone and university follow the user's privacy settings unless the caller is the owner or an admin. A previous username answers with 301 to the current one.
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id path string true "User ID" format(uuid)
+// @Param        username path string true "Username"
 // @Success      200 {object} map[string]interface{} "User profile with gamification stats"
+// @Success      301 {object} map[string]interface{} "Username changed, see Location"
 // @Failure      400 {object} map[string]interface{} "Invalid ID"
 // @Failure      404 {object} map[string]interface{} "User not found"
 // @Router       /users/{id} [get]
+// @Router       /u/{username} [get]
 func (h *UserHandler) Get(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		utils.BadRequest(c, "ID tidak valid")
-		return
-	}
+	var user *models.User
+	if username := c.Param("username"); username != "" {
+		found, moved, err := services.ResolveUsername(username)
+		if err != nil {
+			utils.NotFound(c, "user tidak ditemukan")
+			return
+		}
+		if moved {
+			utils.MovedPermanently(c, "/api/v1/u/"+found.Username)
+			return
+		}
+		user = found
+	} else {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			utils.BadRequest(c, "ID tidak valid")
+			return
+		}
 
-	user, err := services.GetUserByID(id)
-	if err != nil {
-		utils.NotFound(c, err.Error())
-		return
+		user, err = services.GetUserByID(id)
+		if err != nil || user.IsDeleted() {
+			utils.NotFound(c, "user tidak ditemukan")
+			return
+		}
 	}
 
 	stats := services.GetUserGamificationStats(user.TotalExp)
 
+	// The owner and admins see the full profile, everyone else the public projection
+	var profile interface{} = user.ToPublicResponse()
+	if middleware.Can(c, policy.ActionRead, user) {
+		profile = user.ToResponse()
+	}
+
 	utils.Success(c, gin.H{
-		"user":         user.ToResponse(),
+		"user":         profile,
 		"gamification": stats,
 	})
 }
@@ -129,10 +156,8 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	currentUser := middleware.GetCurrentUser(c)
-
-	// Only allow self-update or admin update
-	if currentUser.ID != id && currentUser.Role != models.RoleAdmin {
+	// Only allow self-update or user.update.any
+	if !middleware.Can(c, policy.ActionUpdate, &models.User{ID: id}) {
 		utils.Forbidden(c, "Tidak diizinkan mengubah profil user lain")
 		return
 	}
@@ -173,8 +198,13 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-	if err := db.Delete(&models.User{}, "id = ?", id).Error; err != nil {
+	if _, err := services.GetUserByID(id); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	// Anonymize instead of deleting the row, so transactions and comments keep their references
+	if err := services.AnonymizeUser(id); err != nil {
 		utils.InternalServerError(c, "Gagal menghapus user")
 		return
 	}
@@ -273,6 +303,125 @@ func (h *UserHandler) Unblock(c *gin.Context) {
 	utils.SuccessWithMessage(c, "User berhasil dibuka blokirnya", nil)
 }
 
+// ForceLogout godoc
+// @Summary      Force logout user
+// @Description  Revoke every session and personal access token of a user without blocking the account (admin only)
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "User sessions and tokens revoked"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "User not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /users/{id}/logout [post]
+func (h *UserHandler) ForceLogout(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	if _, err := services.GetUserByID(id); err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	revoked, err := services.RevokeAllSessions(id)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mencabut sesi user")
+		return
+	}
+
+	revokedTokens, err := services.RevokeAllPersonalAccessTokens(id)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mencabut token akses user")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Semua sesi dan token akses user berhasil dicabut", gin.H{
+		"revoked":       revoked,
+		"revokedTokens": revokedTokens,
+	})
+}
+
+// ClearLockout godoc
+// @Summary      Clear login lockout
+// @Description  Reset the failed login counter of a user, and optionally of an IP address (admin only)
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID" format(uuid)
+// @Param        ip query string false "IP address to clear as well"
+// @Success      200 {object} map[string]interface{} "Lockout cleared"
+// @Failure      400 {object} map[string]interface{} "Invalid ID"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Failure      404 {object} map[string]interface{} "User not found"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /users/{id}/lockout [delete]
+func (h *UserHandler) ClearLockout(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	user, err := services.GetUserByID(id)
+	if err != nil {
+		utils.NotFound(c, err.Error())
+		return
+	}
+
+	if err := services.ClearLoginLockout(user.Email, c.Query("ip")); err != nil {
+		utils.InternalServerError(c, "Gagal menghapus penguncian login")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Penguncian login berhasil dihapus", nil)
+}
+
+// Impersonate godoc
+// @Summary      Impersonate user
+// @Description  Issue a short-lived access token to act as a regular user for support (admin only). The token is flagged with impersonatedBy, cannot change account settings or make purchases, and is ended with POST /auth/logout.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "User ID" format(uuid)
+// @Success      200 {object} map[string]interface{} "Impersonation token"
+// @Failure      400 {object} map[string]interface{} "Invalid ID or user cannot be impersonated"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      403 {object} map[string]interface{} "Forbidden"
+// @Router       /users/{id}/impersonate [post]
+func (h *UserHandler) Impersonate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	result, err := services.StartImpersonation(currentUser, id, clientInfo(c))
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Impersonasi dimulai", gin.H{
+		"user":           result.User.ToResponse(),
+		"impersonatedBy": currentUser.ID,
+		"token": gin.H{
+			"accessToken": result.AccessToken,
+			"expiresIn":   result.ExpiresIn,
+		},
+	})
+}
+
 // Leaderboard godoc
 // @Summary      Get leaderboard
 // @Description  Get top users by EXP
@@ -280,6 +429,8 @@ func (h *UserHandler) Unblock(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        limit query int false "Number of users to return" default(10)
+// @Param        verifiedStudents query bool false "Only include verified students"
+// @Param        universityId query string false "Only include verified students of this university" format(uuid)
 // @Success      200 {object} map[string]interface{} "Leaderboard entries"
 // @Router       /users/leaderboard [get]
 func (h *UserHandler) Leaderboard(c *gin.Context) {
@@ -290,18 +441,25 @@ func (h *UserHandler) Leaderboard(c *gin.Context) {
 		limit = 10
 	}
 
+	query := db.Preload("VerifiedUniversity").Where("status = ? AND hide_from_leaderboard = ?", models.StatusActive, false)
+	if c.Query("verifiedStudents") == "true" {
+		query = query.Where("student_verified_at IS NOT NULL")
+	}
+	if universityID := c.Query("universityId"); universityID != "" {
+		query = query.Where("verified_university_id = ? AND student_verified_at IS NOT NULL", universityID)
+	}
+
 	var users []models.User
-	db.Where("status = ?", models.StatusActive).
-		Order("total_exp DESC").
+	query.Order("total_exp DESC").
 		Limit(limit).
 		Find(&users)
 
 	type LeaderboardEntry struct {
-		Rank       int                 `json:"rank"`
-		User       models.UserResponse `json:"user"`
-		TotalExp   int                 `json:"totalExp"`
-		Level      int                 `json:"level"`
-		LevelTitle string              `json:"levelTitle"`
+		Rank       int                       `json:"rank"`
+		User       models.PublicUserResponse `json:"user"`
+		TotalExp   int                       `json:"totalExp"`
+		Level      int                       `json:"level"`
+		LevelTitle string                    `json:"levelTitle"`
 	}
 
 	entries := make([]LeaderboardEntry, len(users))
@@ -309,7 +467,7 @@ func (h *UserHandler) Leaderboard(c *gin.Context) {
 		stats := services.GetUserGamificationStats(user.TotalExp)
 		entries[i] = LeaderboardEntry{
 			Rank:       i + 1,
-			User:       user.ToResponse(),
+			User:       user.ToPublicResponse(),
 			TotalExp:   user.TotalExp,
 			Level:      stats.Level,
 			LevelTitle: stats.LevelTitle,
@@ -320,3 +478,225 @@ func (h *UserHandler) Leaderboard(c *gin.Context) {
 		"leaderboard": entries,
 	})
 }
+
+// RequestStudentVerification godoc
+// @Summary      Start student verification
+// @Description  Send a verification code to a campus email of a registered university
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.StudentVerificationInput true "Campus email"
+// @Success      200 {object} map[string]interface{} "Code sent"
+// @Failure      400 {object} map[string]interface{} "Unknown domain or email already used"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/student-verification [post]
+func (h *UserHandler) RequestStudentVerification(c *gin.Context) {
+	var input services.StudentVerificationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.RequestStudentVerification(currentUser.ID, &input); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Kode verifikasi telah dikirim ke email kampus Anda", nil)
+}
+
+// ConfirmStudentVerification godoc
+// @Summary      Confirm student verification
+// @Description  Confirm the campus email with the code and become a verified student
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.ConfirmStudentVerificationInput true "Verification code"
+// @Success      200 {object} map[string]interface{} "Verified user"
+// @Failure      400 {object} map[string]interface{} "Invalid or expired code"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/student-verification/confirm [post]
+func (h *UserHandler) ConfirmStudentVerification(c *gin.Context) {
+	var input services.ConfirmStudentVerificationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	if err := utils.Validate(&input); err != nil {
+		errors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"errors":  errors,
+		})
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	user, err := services.ConfirmStudentVerification(currentUser.ID, &input)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Status mahasiswa berhasil diverifikasi", user.ToResponse())
+}
+
+// RemoveStudentVerification godoc
+// @Summary      Remove student verification
+// @Description  Remove the campus email and verified student status of the current user
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Verification removed"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/student-verification [delete]
+func (h *UserHandler) RemoveStudentVerification(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.RemoveStudentVerification(currentUser.ID); err != nil {
+		utils.InternalServerError(c, "Gagal menghapus verifikasi mahasiswa")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Verifikasi mahasiswa berhasil dihapus", nil)
+}
+
+// UpdatePrivacy godoc
+// @Summary      Update privacy settings
+// @Description  Choose whether email, phone and university are shown to other users, and whether the user appears on the leaderboard
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.UpdatePrivacyInput true "Privacy settings to change"
+// @Success      200 {object} map[string]interface{} "Updated user profile"
+// @Failure      400 {object} map[string]interface{} "Invalid input"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/privacy [put]
+func (h *UserHandler) UpdatePrivacy(c *gin.Context) {
+	var input services.UpdatePrivacyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BadRequest(c, "Data tidak valid")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	user, err := services.UpdatePrivacySettings(currentUser.ID, &input)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal menyimpan pengaturan privasi")
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Pengaturan privasi berhasil diperbarui", user.ToResponse())
+}
+
+// RequestDeletion godoc
+// @Summary      Request account deletion
+// @Description  Schedule the current account to be anonymized after a grace period. Accounts with a password must confirm it.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body services.AccountDeletionInput false "Current password"
+// @Success      200 {object} map[string]interface{} "Deletion scheduled"
+// @Failure      400 {object} map[string]interface{} "Wrong password or already scheduled"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/deletion [post]
+func (h *UserHandler) RequestDeletion(c *gin.Context) {
+	var input services.AccountDeletionInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			utils.BadRequest(c, "Data tidak valid")
+			return
+		}
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	scheduledAt, err := services.RequestAccountDeletion(currentUser.ID, &input)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Akun Anda dijadwalkan untuk dihapus", gin.H{
+		"deletionScheduledAt": scheduledAt,
+	})
+}
+
+// CancelDeletion godoc
+// @Summary      Cancel account deletion
+// @Description  Cancel a pending deletion of the current account
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} map[string]interface{} "Deletion cancelled"
+// @Failure      400 {object} map[string]interface{} "No deletion scheduled"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Router       /users/me/deletion [delete]
+func (h *UserHandler) CancelDeletion(c *gin.Context) {
+	currentUser := middleware.GetCurrentUser(c)
+	if err := services.CancelAccountDeletion(currentUser.ID); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "Penghapusan akun dibatalkan", nil)
+}
+
+// Export godoc
+// @Summary      Export personal data
+// @Description  Download the profile, projects, articles, comments, likes and transactions of the current user as a ZIP archive, or as a single JSON file with format=json
+// @Tags         users
+// @Produce      application/zip
+// @Produce      json
+// @Security     BearerAuth
+// @Param        format query string false "zip or json" default(zip)
+// @Success      200 {file} file "Data export"
+// @Failure      400 {object} map[string]interface{} "Invalid format"
+// @Failure      401 {object} map[string]interface{} "Unauthorized"
+// @Failure      500 {object} map[string]interface{} "Internal server error"
+// @Router       /users/me/export [get]
+func (h *UserHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "zip")
+	if format != "zip" && format != "json" {
+		utils.BadRequest(c, "Format harus zip atau json")
+		return
+	}
+
+	currentUser := middleware.GetCurrentUser(c)
+	export, err := services.BuildUserExport(currentUser.ID)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal menyiapkan ekspor data")
+		return
+	}
+
+	filename := "campus-project-hub-export-" + export.ExportedAt.Format("20060102")
+	c.Header("Cache-Control", "no-store")
+
+	if format == "json" {
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`.json"`)
+		c.IndentedJSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`.zip"`)
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	if err := services.WriteUserExportZip(c.Writer, export); err != nil {
+		log.Printf("Failed to write data export for %s: %v", currentUser.ID, err)
+	}
+}
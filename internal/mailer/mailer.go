@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"log"
+	"sync"
+
+	"github.com/campus-project-hub/api/internal/config"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var (
+	current Mailer
+	mu      sync.RWMutex
+)
+
+// GetMailer returns the configured mailer. Without an SMTP host emails are only logged.
+func GetMailer() Mailer {
+	mu.RLock()
+	m := current
+	mu.RUnlock()
+	if m != nil {
+		return m
+	}
+
+	cfg := config.GetConfig()
+	if cfg.Mail.Host != "" {
+		m = NewSMTPMailer(cfg.Mail)
+	} else {
+		m = &LogMailer{}
+	}
+
+	SetMailer(m)
+	return m
+}
+
+// SetMailer replaces the mailer used by the application
+func SetMailer(m Mailer) {
+	mu.Lock()
+	current = m
+	mu.Unlock()
+}
+
+// LogMailer writes emails to the log instead of sending them
+type LogMailer struct{}
+
+func (l *LogMailer) Send(msg Message) error {
+	log.Printf("📧 Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/services"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,9 +16,14 @@ const (
 	BearerPrefix        = "Bearer "
 	UserContextKey      = "user"
 	UserIDContextKey    = "userId"
+	SessionContextKey   = "sessionId"
+	// AccessTokenContextKey is set instead of SessionContextKey for personal access tokens
+	AccessTokenContextKey = "accessToken"
+	// ImpersonatorContextKey holds the admin ID when an admin is impersonating the user
+	ImpersonatorContextKey = "impersonatedBy"
 )
 
-// AuthMiddleware validates JWT token and sets user in context
+// AuthMiddleware validates a JWT or personal access token and sets user in context
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader(AuthorizationHeader)
@@ -34,35 +40,88 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, BearerPrefix)
-		claims, err := utils.ValidateToken(tokenString)
+		if services.IsPersonalAccessToken(tokenString) {
+			authenticateAccessToken(c, tokenString)
+			return
+		}
+
+		claims, err := utils.ValidateAccessToken(tokenString)
 		if err != nil {
 			utils.Unauthorized(c, "Token tidak valid atau sudah kedaluwarsa")
 			c.Abort()
 			return
 		}
 
-		// Fetch user from database
-		var user models.User
-		if err := database.GetDB().First(&user, "id = ?", claims.UserID).Error; err != nil {
-			utils.Unauthorized(c, "User tidak ditemukan")
+		// Reject tokens whose session has been revoked (logout, refresh token reuse)
+		sessionID, err := claims.SessionID()
+		if err != nil || !services.IsSessionActive(sessionID, claims.UserID) {
+			utils.Unauthorized(c, "Sesi telah berakhir, silakan login kembali")
 			c.Abort()
 			return
 		}
 
-		// Check if user is blocked
-		if user.Status == models.StatusBlocked {
-			utils.Forbidden(c, "Akun Anda telah diblokir")
-			c.Abort()
+		user, ok := loadActiveUser(c, claims.UserID)
+		if !ok {
 			return
 		}
 
-		c.Set(UserContextKey, &user)
+		c.Set(UserContextKey, user)
 		c.Set(UserIDContextKey, user.ID)
+		c.Set(SessionContextKey, sessionID)
+		if claims.ImpersonatedBy != nil {
+			c.Set(ImpersonatorContextKey, *claims.ImpersonatedBy)
+		}
 		c.Next()
 	}
 }
 
-// OptionalAuthMiddleware tries to authenticate but doesn't require it
+// authenticateAccessToken authenticates a request made with a personal access token
+func authenticateAccessToken(c *gin.Context, tokenString string) {
+	token, err := services.AuthenticatePersonalAccessToken(tokenString)
+	if err != nil {
+		utils.Unauthorized(c, err.Error())
+		c.Abort()
+		return
+	}
+
+	user, ok := loadActiveUser(c, token.UserID)
+	if !ok {
+		return
+	}
+
+	c.Set(UserContextKey, user)
+	c.Set(UserIDContextKey, user.ID)
+	c.Set(AccessTokenContextKey, token)
+	c.Next()
+}
+
+// loadActiveUser fetches the user of a valid credential, aborting when missing or blocked
+func loadActiveUser(c *gin.Context, userID uuid.UUID) (*models.User, bool) {
+	var user models.User
+	if err := database.GetDB().Preload("VerifiedUniversity").First(&user, "id = ?", userID).Error; err != nil {
+		utils.Unauthorized(c, "User tidak ditemukan")
+		c.Abort()
+		return nil, false
+	}
+
+	if user.IsDeleted() {
+		utils.Unauthorized(c, "User tidak ditemukan")
+		c.Abort()
+		return nil, false
+	}
+
+	// Check if user is blocked
+	if user.Status == models.StatusBlocked {
+		utils.Forbidden(c, "Akun Anda telah diblokir")
+		c.Abort()
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// OptionalAuthMiddleware tries to authenticate but doesn't require it.
+// Personal access tokens are only honoured when they carry the read scope.
 func OptionalAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader(AuthorizationHeader)
@@ -77,17 +136,41 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, BearerPrefix)
-		claims, err := utils.ValidateToken(tokenString)
+		if services.IsPersonalAccessToken(tokenString) {
+			token, err := services.AuthenticatePersonalAccessToken(tokenString)
+			if err == nil && token.HasScope(models.ScopeRead) {
+				var user models.User
+				if err := database.GetDB().First(&user, "id = ?", token.UserID).Error; err == nil && user.Status != models.StatusBlocked {
+					c.Set(UserContextKey, &user)
+					c.Set(UserIDContextKey, user.ID)
+					c.Set(AccessTokenContextKey, token)
+				}
+			}
+			c.Next()
+			return
+		}
+
+		claims, err := utils.ValidateAccessToken(tokenString)
 		if err != nil {
 			c.Next()
 			return
 		}
 
+		sessionID, err := claims.SessionID()
+		if err != nil || !services.IsSessionActive(sessionID, claims.UserID) {
+			c.Next()
+			return
+		}
+
 		var user models.User
 		if err := database.GetDB().First(&user, "id = ?", claims.UserID).Error; err == nil {
 			if user.Status != models.StatusBlocked {
 				c.Set(UserContextKey, &user)
 				c.Set(UserIDContextKey, user.ID)
+				c.Set(SessionContextKey, sessionID)
+				if claims.ImpersonatedBy != nil {
+					c.Set(ImpersonatorContextKey, *claims.ImpersonatedBy)
+				}
 			}
 		}
 
@@ -110,3 +193,27 @@ func GetCurrentUserID(c *gin.Context) uuid.UUID {
 	}
 	return uuid.Nil
 }
+
+// GetCurrentSessionID retrieves the session of the authenticated request from context
+func GetCurrentSessionID(c *gin.Context) uuid.UUID {
+	if sessionID, exists := c.Get(SessionContextKey); exists {
+		return sessionID.(uuid.UUID)
+	}
+	return uuid.Nil
+}
+
+// GetCurrentAccessToken returns the personal access token of the request, or nil for session logins
+func GetCurrentAccessToken(c *gin.Context) *models.PersonalAccessToken {
+	if token, exists := c.Get(AccessTokenContextKey); exists {
+		return token.(*models.PersonalAccessToken)
+	}
+	return nil
+}
+
+// GetImpersonatorID returns the admin impersonating the current user, or uuid.Nil
+func GetImpersonatorID(c *gin.Context) uuid.UUID {
+	if impersonatorID, exists := c.Get(ImpersonatorContextKey); exists {
+		return impersonatorID.(uuid.UUID)
+	}
+	return uuid.Nil
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DenyImpersonation blocks account changes and payments while an admin is
+// impersonating the user, such as changing the password or buying a project
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetImpersonatorID(c) != uuid.Nil {
+			utils.Forbidden(c, "Endpoint ini tidak tersedia saat impersonasi")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/campus-project-hub/api/internal/services"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// staffDenial explains why staff permissions are unavailable to the request, or returns
+// "" when they may be used
+func staffDenial(c *gin.Context, user *models.User) string {
+	// Moderation and admin actions always need an interactive login
+	if GetCurrentAccessToken(c) != nil {
+		return "Endpoint ini tidak dapat diakses dengan token akses pribadi"
+	}
+
+	// Staff powers are never available through an impersonated account
+	if GetImpersonatorID(c) != uuid.Nil {
+		return "Endpoint ini tidak tersedia saat impersonasi"
+	}
+
+	// Privileged roles must enroll in 2FA before using their powers
+	if services.IsTwoFactorRequired(user.Role) && !user.IsTwoFactorEnabled() {
+		return "Aktifkan autentikasi dua faktor untuk mengakses fitur ini"
+	}
+
+	return ""
+}
+
+// Has is policy.Has for the current request. Like RequirePermission, it only grants
+// permissions to interactive, non-impersonated logins with 2FA where it is required.
+func Has(c *gin.Context, permission policy.Permission) bool {
+	user := GetCurrentUser(c)
+	return policy.Has(user, permission) && staffDenial(c, user) == ""
+}
+
+// Can is policy.Can for the current request. Owners and collaborators act on their own
+// resources as usual; access through a ".any" permission is gated like Has.
+func Can(c *gin.Context, action policy.Action, resource interface{}) bool {
+	user := GetCurrentUser(c)
+	if policy.Owns(user, action, resource) {
+		return true
+	}
+	return Has(c, policy.AnyPermission(action, resource))
+}
+
+// RequirePermission middleware checks if the user's role grants any of the permissions
+func RequirePermission(permissions ...policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetCurrentUser(c)
+		if user == nil {
+			utils.Unauthorized(c, "Autentikasi diperlukan")
+			c.Abort()
+			return
+		}
+
+		if reason := staffDenial(c, user); reason != "" {
+			utils.Forbidden(c, reason)
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if policy.Has(user, permission) {
+				c.Next()
+				return
+			}
+		}
+
+		utils.Forbidden(c, "Akses ditolak")
+		c.Abort()
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// useTwoFactorRequiredRoles replaces the loaded configuration for the duration of a test
+func useTwoFactorRequiredRoles(t *testing.T, roles ...string) {
+	t.Helper()
+
+	previous := config.AppConfig_
+	config.AppConfig_ = &config.Config{Auth: config.AuthConfig{TwoFactorRequiredRoles: roles}}
+	t.Cleanup(func() { config.AppConfig_ = previous })
+}
+
+func newStaffUser(role models.UserRole, twoFactor bool) *models.User {
+	user := &models.User{ID: uuid.New(), Role: role}
+	if twoFactor {
+		secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		enabledAt := time.Now()
+		user.TOTPSecret = &secret
+		user.TOTPEnabledAt = &enabledAt
+	}
+	return user
+}
+
+// requestAs describes how the current request was authenticated
+type requestAs struct {
+	user         *models.User
+	accessToken  bool
+	impersonated bool
+}
+
+func newTestContext(r requestAs) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if r.user != nil {
+		c.Set(UserContextKey, r.user)
+		c.Set(UserIDContextKey, r.user.ID)
+	}
+	if r.accessToken {
+		c.Set(AccessTokenContextKey, &models.PersonalAccessToken{ID: uuid.New(), UserID: r.user.ID})
+	} else {
+		c.Set(SessionContextKey, uuid.New())
+	}
+	if r.impersonated {
+		c.Set(ImpersonatorContextKey, uuid.New())
+	}
+	return c, recorder
+}
+
+func TestHas(t *testing.T) {
+	useTwoFactorRequiredRoles(t, string(models.RoleAdmin), string(models.RoleModerator))
+
+	admin := newStaffUser(models.RoleAdmin, true)
+	tests := []struct {
+		name string
+		as   requestAs
+		want bool
+	}{
+		{"interactive admin with 2FA", requestAs{user: admin}, true},
+		{"personal access token", requestAs{user: admin, accessToken: true}, false},
+		{"impersonated", requestAs{user: admin, impersonated: true}, false},
+		{"2FA required but not enabled", requestAs{user: newStaffUser(models.RoleAdmin, false)}, false},
+		{"role without the permission", requestAs{user: newStaffUser(models.RoleModerator, true)}, false},
+		{"regular user", requestAs{user: newStaffUser(models.RoleUser, false)}, false},
+		{"anonymous", requestAs{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(tt.as)
+			if got := Has(c, policy.UserImpersonate); got != tt.want {
+				t.Errorf("Has() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasWithoutTwoFactorRequirement(t *testing.T) {
+	useTwoFactorRequiredRoles(t)
+
+	c, _ := newTestContext(requestAs{user: newStaffUser(models.RoleAdmin, false)})
+	if !Has(c, policy.UserImpersonate) {
+		t.Error("Has() denied an admin without 2FA although no role requires it")
+	}
+}
+
+func TestCan(t *testing.T) {
+	useTwoFactorRequiredRoles(t, string(models.RoleAdmin))
+
+	owner := newStaffUser(models.RoleUser, false)
+	admin := newStaffUser(models.RoleAdmin, true)
+	project := &models.Project{ID: uuid.New(), UserID: owner.ID}
+
+	tests := []struct {
+		name string
+		as   requestAs
+		want bool
+	}{
+		// Owners keep acting on their own resources with a personal access token
+		{"owner with personal access token", requestAs{user: owner, accessToken: true}, true},
+		{"admin", requestAs{user: admin}, true},
+		{"admin with personal access token", requestAs{user: admin, accessToken: true}, false},
+		{"impersonated admin", requestAs{user: admin, impersonated: true}, false},
+		{"admin without 2FA", requestAs{user: newStaffUser(models.RoleAdmin, false)}, false},
+		{"stranger", requestAs{user: newStaffUser(models.RoleUser, false)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext(tt.as)
+			if got := Can(c, policy.ActionUpdate, project); got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	useTwoFactorRequiredRoles(t, string(models.RoleAdmin))
+
+	admin := newStaffUser(models.RoleAdmin, true)
+	tests := []struct {
+		name string
+		as   requestAs
+		want int
+	}{
+		{"interactive admin with 2FA", requestAs{user: admin}, http.StatusOK},
+		{"personal access token", requestAs{user: admin, accessToken: true}, http.StatusForbidden},
+		{"impersonated", requestAs{user: admin, impersonated: true}, http.StatusForbidden},
+		{"2FA required but not enabled", requestAs{user: newStaffUser(models.RoleAdmin, false)}, http.StatusForbidden},
+		{"regular user", requestAs{user: newStaffUser(models.RoleUser, false)}, http.StatusForbidden},
+		{"anonymous", requestAs{}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder := newTestContext(tt.as)
+			RequirePermission(policy.UserImpersonate)(c)
+			if !c.IsAborted() {
+				c.Status(http.StatusOK)
+				c.Writer.WriteHeaderNow()
+			}
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
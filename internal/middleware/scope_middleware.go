@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope lets personal access tokens through only when they hold one of the scopes.
+// Session logins are not restricted by scopes.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := GetCurrentAccessToken(c)
+		if token == nil {
+			c.Next()
+			return
+		}
+
+		for _, scope := range scopes {
+			if token.HasScope(scope) {
+				c.Next()
+				return
+			}
+		}
+
+		utils.Forbidden(c, "Token akses tidak memiliki izin untuk endpoint ini")
+		c.Abort()
+	}
+}
+
+// RequireSessionAuth rejects personal access tokens, for account management and
+// other endpoints that should only be reachable from an interactive login
+func RequireSessionAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetCurrentAccessToken(c) != nil {
+			utils.Forbidden(c, "Endpoint ini tidak dapat diakses dengan token akses pribadi")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail blocks users with an unverified email when the policy is enabled
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.GetConfig().Auth.RequireVerifiedEmail {
+			c.Next()
+			return
+		}
+
+		user := GetCurrentUser(c)
+		if user == nil {
+			utils.Unauthorized(c, "Autentikasi diperlukan")
+			c.Abort()
+			return
+		}
+
+		if !user.IsEmailVerified() {
+			utils.Forbidden(c, "Silakan verifikasi email Anda terlebih dahulu")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -42,18 +42,18 @@ func (a *Article) BeforeCreate(tx *gorm.DB) error {
 }
 
 type ArticleResponse struct {
-	ID           uuid.UUID     `json:"id"`
-	Title        string        `json:"title"`
-	Excerpt      *string       `json:"excerpt"`
-	Content      *string       `json:"content"`
-	ThumbnailURL *string       `json:"thumbnailUrl"`
-	Category     *string       `json:"category"`
-	ReadingTime  int           `json:"readingTime"`
-	Status       ArticleStatus `json:"status"`
-	Views        int           `json:"views"`
-	PublishedAt  *time.Time    `json:"publishedAt"`
-	Author       UserResponse  `json:"author"`
-	CreatedAt    time.Time     `json:"createdAt"`
+	ID           uuid.UUID          `json:"id"`
+	Title        string             `json:"title"`
+	Excerpt      *string            `json:"excerpt"`
+	Content      *string            `json:"content"`
+	ThumbnailURL *string            `json:"thumbnailUrl"`
+	Category     *string            `json:"category"`
+	ReadingTime  int                `json:"readingTime"`
+	Status       ArticleStatus      `json:"status"`
+	Views        int                `json:"views"`
+	PublishedAt  *time.Time         `json:"publishedAt"`
+	Author       PublicUserResponse `json:"author"`
+	CreatedAt    time.Time          `json:"createdAt"`
 }
 
 func (a *Article) ToResponse() ArticleResponse {
@@ -68,7 +68,7 @@ func (a *Article) ToResponse() ArticleResponse {
 		Status:       a.Status,
 		Views:        a.Views,
 		PublishedAt:  a.PublishedAt,
-		Author:       a.User.ToResponse(),
+		Author:       a.User.ToPublicResponse(),
 		CreatedAt:    a.CreatedAt,
 	}
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audit log actions
+const (
+	AuditImpersonationStart = "impersonation.start"
+	AuditImpersonationStop  = "impersonation.stop"
+	AuditOwnershipForced    = "ownership.transfer.force"
+)
+
+// AuditLog records a sensitive action taken by a staff member
+type AuditLog struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ActorID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"actorId"`
+	Action     string     `gorm:"size:100;not null;index" json:"action"`
+	TargetType string     `gorm:"size:50" json:"targetType"`
+	TargetID   *uuid.UUID `gorm:"type:uuid;index" json:"targetId"`
+	IPAddress  *string    `gorm:"size:45" json:"ipAddress"`
+	UserAgent  *string    `gorm:"type:text" json:"userAgent"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
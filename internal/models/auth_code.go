@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthCode is a single-use code handed to the frontend after an OAuth login.
+// The frontend trades it for a token pair so tokens never appear in URLs.
+type AuthCode struct {
+	CodeHash  string     `gorm:"primaryKey;size:64" json:"-"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"userId"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
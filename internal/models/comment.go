@@ -27,17 +27,17 @@ func (c *Comment) BeforeCreate(tx *gorm.DB) error {
 }
 
 type CommentResponse struct {
-	ID        uuid.UUID    `json:"id"`
-	Content   string       `json:"content"`
-	User      UserResponse `json:"user"`
-	CreatedAt time.Time    `json:"createdAt"`
+	ID        uuid.UUID          `json:"id"`
+	Content   string             `json:"content"`
+	User      PublicUserResponse `json:"user"`
+	CreatedAt time.Time          `json:"createdAt"`
 }
 
 func (c *Comment) ToResponse() CommentResponse {
 	return CommentResponse{
 		ID:        c.ID,
 		Content:   c.Content,
-		User:      c.User.ToResponse(),
+		User:      c.User.ToPublicResponse(),
 		CreatedAt: c.CreatedAt,
 	}
 }
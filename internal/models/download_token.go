@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DownloadToken records a signed download URL so it can only be used once. Its ID is
+// the jti claim of the token.
+type DownloadToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	AssetID   uuid.UUID  `gorm:"type:uuid;not null" json:"assetId"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"userId"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
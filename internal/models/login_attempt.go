@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// LoginAttempt counts recent failed logins for an account or IP address.
+// Key is "account:<email>" or "ip:<address>".
+type LoginAttempt struct {
+	Key           string     `gorm:"size:320;primary_key" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `gorm:"not null" json:"lastFailureAt"`
+	LockedUntil   *time.Time `json:"lockedUntil"`
+}
+
+// IsLocked reports whether the key is locked out at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// IsStale reports whether the counter has been quiet for longer than the window,
+// counting from the last failure or from the end of the lockout, whichever is later
+func (a *LoginAttempt) IsStale(now time.Time, window time.Duration) bool {
+	last := a.LastFailureAt
+	if a.LockedUntil != nil && a.LockedUntil.After(last) {
+		last = *a.LockedUntil
+	}
+	return now.Sub(last) > window
+}
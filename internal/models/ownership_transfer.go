@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransferResourceType string
+type TransferStatus string
+
+const (
+	TransferResourceProject TransferResourceType = "project"
+	TransferResourceArticle TransferResourceType = "article"
+)
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferAccepted  TransferStatus = "accepted"
+	TransferDeclined  TransferStatus = "declined"
+	TransferCancelled TransferStatus = "cancelled"
+	// TransferForced is a transfer an admin carried out without the recipient's consent
+	TransferForced TransferStatus = "forced"
+)
+
+// OwnershipTransfer moves a project or article to another user. Requests stay in the
+// table after they are settled and form the resource's ownership history.
+type OwnershipTransfer struct {
+	ID           uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ResourceType TransferResourceType `gorm:"size:20;not null;index:idx_ownership_transfers_resource" json:"resourceType"`
+	ResourceID   uuid.UUID            `gorm:"type:uuid;not null;index:idx_ownership_transfers_resource" json:"resourceId"`
+	FromUserID   uuid.UUID            `gorm:"type:uuid;not null;index" json:"fromUserId"`
+	ToUserID     uuid.UUID            `gorm:"type:uuid;not null;index" json:"toUserId"`
+	RequestedBy  uuid.UUID            `gorm:"type:uuid;not null" json:"requestedBy"`
+	Status       TransferStatus       `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Message      *string              `gorm:"type:text" json:"message"`
+	RespondedAt  *time.Time           `json:"respondedAt"`
+	CreatedAt    time.Time            `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	FromUser User `gorm:"foreignKey:FromUserID" json:"-"`
+	ToUser   User `gorm:"foreignKey:ToUserID" json:"-"`
+}
+
+func (t *OwnershipTransfer) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsPending reports whether the transfer still waits for the recipient
+func (t *OwnershipTransfer) IsPending() bool {
+	return t.Status == TransferPending
+}
+
+// OwnershipTransferResponse for API response. Title is the resource's title when it was loaded.
+type OwnershipTransferResponse struct {
+	ID           uuid.UUID            `json:"id"`
+	ResourceType TransferResourceType `json:"resourceType"`
+	ResourceID   uuid.UUID            `json:"resourceId"`
+	Title        string               `json:"title,omitempty"`
+	From         PublicUserResponse   `json:"from"`
+	To           PublicUserResponse   `json:"to"`
+	RequestedBy  uuid.UUID            `json:"requestedBy"`
+	Status       TransferStatus       `json:"status"`
+	Message      *string              `json:"message"`
+	RespondedAt  *time.Time           `json:"respondedAt"`
+	CreatedAt    time.Time            `json:"createdAt"`
+}
+
+func (t *OwnershipTransfer) ToResponse(title string) OwnershipTransferResponse {
+	return OwnershipTransferResponse{
+		ID:           t.ID,
+		ResourceType: t.ResourceType,
+		ResourceID:   t.ResourceID,
+		Title:        title,
+		From:         t.FromUser.ToPublicResponse(),
+		To:           t.ToUser.ToPublicResponse(),
+		RequestedBy:  t.RequestedBy,
+		Status:       t.Status,
+		Message:      t.Message,
+		RespondedAt:  t.RespondedAt,
+		CreatedAt:    t.CreatedAt,
+	}
+}
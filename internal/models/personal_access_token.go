@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Personal access token scopes
+const (
+	ScopeRead          = "read"
+	ScopeProjectsWrite = "projects:write"
+	ScopeArticlesWrite = "articles:write"
+)
+
+// TokenScopes lists every scope a personal access token can be granted
+var TokenScopes = []string{ScopeRead, ScopeProjectsWrite, ScopeArticlesWrite}
+
+// PersonalAccessToken is a long-lived credential for scripts and CI.
+// Only the hash is stored; the plain token is shown once on creation.
+type PersonalAccessToken struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"userId"`
+	Name       string         `gorm:"not null;size:100" json:"name"`
+	TokenHash  string         `gorm:"uniqueIndex;not null;size:64" json:"-"`
+	Prefix     string         `gorm:"not null;size:16" json:"prefix"`
+	Scopes     pq.StringArray `gorm:"type:text[]" json:"scopes"`
+	ExpiresAt  *time.Time     `json:"expiresAt"`
+	LastUsedAt *time.Time     `json:"lastUsedAt"`
+	RevokedAt  *time.Time     `json:"-"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// IsActive reports whether the token is neither revoked nor expired
+func (t *PersonalAccessToken) IsActive() bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || time.Now().Before(*t.ExpiresAt)
+}
+
+// HasScope reports whether the token was granted the scope
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
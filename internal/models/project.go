@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
@@ -17,15 +18,19 @@ const (
 )
 
 const (
-	ProjectStatusPublished ProjectStatus = "published"
-	ProjectStatusDraft     ProjectStatus = "draft"
-	ProjectStatusBlocked   ProjectStatus = "blocked"
+	ProjectStatusPublished     ProjectStatus = "published"
+	ProjectStatusDraft         ProjectStatus = "draft"
+	ProjectStatusPendingReview ProjectStatus = "pending_review"
+	ProjectStatusRejected      ProjectStatus = "rejected"
+	ProjectStatusScheduled     ProjectStatus = "scheduled"
+	ProjectStatusBlocked       ProjectStatus = "blocked"
 )
 
 type Project struct {
 	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID       uuid.UUID      `gorm:"type:uuid;not null" json:"userId"`
+	UserID       uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_projects_user_slug" json:"userId"`
 	Title        string         `gorm:"not null;size:255" json:"title"`
+	Slug         string         `gorm:"not null;size:120;uniqueIndex:idx_projects_user_slug" json:"slug"`
 	Description  *string        `gorm:"type:text" json:"description"`
 	ThumbnailURL *string        `gorm:"type:text" json:"thumbnailUrl"`
 	TechStack    pq.StringArray `gorm:"type:text[]" json:"techStack"`
@@ -37,24 +42,54 @@ type Project struct {
 	Views        int            `gorm:"default:0" json:"views"`
 	Likes        int            `gorm:"default:0" json:"likes"`
 	CategoryID   *uuid.UUID     `gorm:"type:uuid" json:"categoryId"`
+	PublishAt    *time.Time     `json:"publishAt"`
+	PublishedAt  *time.Time     `json:"publishedAt"`
+	ReviewedBy   *uuid.UUID     `gorm:"type:uuid" json:"reviewedBy"`
+	ReviewedAt   *time.Time     `json:"reviewedAt"`
+	ReviewReason *string        `gorm:"type:text" json:"reviewReason"`
 	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 
 	// Relationships
-	User     User           `gorm:"foreignKey:UserID" json:"author,omitempty"`
-	Category *Category      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
-	Images   []ProjectImage `gorm:"foreignKey:ProjectID" json:"images,omitempty"`
-	Comments []Comment      `gorm:"foreignKey:ProjectID" json:"comments,omitempty"`
-	LikedBy  []User         `gorm:"many2many:project_likes" json:"likedBy,omitempty"`
+	User          User                  `gorm:"foreignKey:UserID" json:"author,omitempty"`
+	Category      *Category             `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Images        []ProjectImage        `gorm:"foreignKey:ProjectID" json:"images,omitempty"`
+	Collaborators []ProjectCollaborator `gorm:"foreignKey:ProjectID" json:"collaborators,omitempty"`
+	Technologies  []Technology          `gorm:"many2many:project_technologies" json:"technologies,omitempty"`
+	Comments      []Comment             `gorm:"foreignKey:ProjectID" json:"comments,omitempty"`
+	LikedBy       []User                `gorm:"many2many:project_likes" json:"likedBy,omitempty"`
 }
 
 func (p *Project) BeforeCreate(tx *gorm.DB) error {
 	if p.ID == uuid.Nil {
 		p.ID = uuid.New()
 	}
+	if p.Slug == "" {
+		slug, err := GenerateProjectSlug(tx.Session(&gorm.Session{NewDB: true}), p.UserID, p.Title, p.ID)
+		if err != nil {
+			return err
+		}
+		p.Slug = slug
+	}
 	return nil
 }
 
+// IsPublished reports whether the project is visible to everyone
+func (p *Project) IsPublished() bool {
+	return p.Status == ProjectStatusPublished
+}
+
+// GenerateProjectSlug derives a slug from the title that is free among the author's projects
+func GenerateProjectSlug(tx *gorm.DB, userID uuid.UUID, title string, projectID uuid.UUID) (string, error) {
+	base := utils.Slugify(title, ProjectSlugMaxLength)
+	if base == "" {
+		base = "project"
+	}
+	return availableSlug(base, ProjectSlugMaxLength, func(candidate string) (bool, error) {
+		return ProjectSlugTaken(tx, userID, candidate, projectID)
+	})
+}
+
 type ProjectImage struct {
 	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	ProjectID uuid.UUID `gorm:"type:uuid;not null" json:"projectId"`
@@ -77,20 +112,26 @@ type ProjectLike struct {
 
 // ProjectResponse for API response
 type ProjectResponse struct {
-	ID           uuid.UUID     `json:"id"`
-	Title        string        `json:"title"`
-	Description  *string       `json:"description"`
-	ThumbnailURL *string       `json:"thumbnailUrl"`
-	Images       []string      `json:"images"`
-	TechStack    []string      `json:"techStack"`
-	Links        ProjectLinks  `json:"links"`
-	Stats        ProjectStats  `json:"stats"`
-	Type         ProjectType   `json:"type"`
-	Price        int           `json:"price,omitempty"`
-	Status       ProjectStatus `json:"status"`
-	Author       UserResponse  `json:"author"`
-	CategoryID   *uuid.UUID    `json:"categoryId"`
-	CreatedAt    time.Time     `json:"createdAt"`
+	ID            uuid.UUID              `json:"id"`
+	Title         string                 `json:"title"`
+	Slug          string                 `json:"slug"`
+	Description   *string                `json:"description"`
+	ThumbnailURL  *string                `json:"thumbnailUrl"`
+	Images        []string               `json:"images"`
+	TechStack     []string               `json:"techStack"`
+	Technologies  []TechnologyResponse   `json:"technologies"`
+	Links         ProjectLinks           `json:"links"`
+	Stats         ProjectStats           `json:"stats"`
+	Type          ProjectType            `json:"type"`
+	Price         int                    `json:"price,omitempty"`
+	Status        ProjectStatus          `json:"status"`
+	PublishAt     *time.Time             `json:"publishAt,omitempty"`
+	PublishedAt   *time.Time             `json:"publishedAt,omitempty"`
+	ReviewReason  *string                `json:"reviewReason,omitempty"`
+	Author        PublicUserResponse     `json:"author"`
+	Collaborators []CollaboratorResponse `json:"collaborators"`
+	CategoryID    *uuid.UUID             `json:"categoryId"`
+	CreatedAt     time.Time              `json:"createdAt"`
 }
 
 type ProjectLinks struct {
@@ -118,13 +159,33 @@ func (p *Project) ToResponse(commentCount int) ProjectResponse {
 		demoURL = *p.DemoURL
 	}
 
+	// Technologies follow the order of the tech stack
+	technologies := make([]TechnologyResponse, 0, len(p.Technologies))
+	for _, name := range p.TechStack {
+		for i := range p.Technologies {
+			if p.Technologies[i].Name == name {
+				technologies = append(technologies, p.Technologies[i].ToResponse())
+				break
+			}
+		}
+	}
+
+	collaborators := make([]CollaboratorResponse, 0, len(p.Collaborators))
+	for i := range p.Collaborators {
+		if p.Collaborators[i].IsAccepted() {
+			collaborators = append(collaborators, p.Collaborators[i].ToResponse())
+		}
+	}
+
 	return ProjectResponse{
 		ID:           p.ID,
 		Title:        p.Title,
+		Slug:         p.Slug,
 		Description:  p.Description,
 		ThumbnailURL: p.ThumbnailURL,
 		Images:       images,
 		TechStack:    p.TechStack,
+		Technologies: technologies,
 		Links: ProjectLinks{
 			Github: githubURL,
 			Demo:   demoURL,
@@ -134,11 +195,15 @@ func (p *Project) ToResponse(commentCount int) ProjectResponse {
 			Likes:        p.Likes,
 			CommentCount: commentCount,
 		},
-		Type:       p.Type,
-		Price:      p.Price,
-		Status:     p.Status,
-		Author:     p.User.ToResponse(),
-		CategoryID: p.CategoryID,
-		CreatedAt:  p.CreatedAt,
+		Type:          p.Type,
+		Price:         p.Price,
+		Status:        p.Status,
+		PublishAt:     p.PublishAt,
+		PublishedAt:   p.PublishedAt,
+		ReviewReason:  p.ReviewReason,
+		Author:        p.User.ToPublicResponse(),
+		Collaborators: collaborators,
+		CategoryID:    p.CategoryID,
+		CreatedAt:     p.CreatedAt,
 	}
 }
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CollaboratorRole string
+type CollaboratorStatus string
+
+const (
+	// CollaboratorOwner manages the team and shares in sales revenue
+	CollaboratorOwner CollaboratorRole = "owner"
+	// CollaboratorMaintainer can edit the project
+	CollaboratorMaintainer CollaboratorRole = "maintainer"
+	// CollaboratorContributor is credited on the project
+	CollaboratorContributor CollaboratorRole = "contributor"
+)
+
+const (
+	CollaboratorPending  CollaboratorStatus = "pending"
+	CollaboratorAccepted CollaboratorStatus = "accepted"
+)
+
+// ProjectCollaborator is a member of a project's team, or an invitation while pending.
+// RevenueShare is the percentage of each sale paid to an owner; the shares of a
+// project's owners add up to 100.
+type ProjectCollaborator struct {
+	ID           uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID    uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_project_collaborators_member" json:"projectId"`
+	UserID       uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_project_collaborators_member;index" json:"userId"`
+	Role         CollaboratorRole   `gorm:"size:20;not null" json:"role"`
+	Status       CollaboratorStatus `gorm:"size:20;not null;default:'pending'" json:"status"`
+	RevenueShare int                `gorm:"not null;default:0" json:"revenueShare"`
+	InvitedBy    *uuid.UUID         `gorm:"type:uuid" json:"invitedBy"`
+	AcceptedAt   *time.Time         `json:"acceptedAt"`
+	CreatedAt    time.Time          `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	Project Project `gorm:"foreignKey:ProjectID" json:"-"`
+	User    User    `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (pc *ProjectCollaborator) BeforeCreate(tx *gorm.DB) error {
+	if pc.ID == uuid.Nil {
+		pc.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsAccepted reports whether the invitee joined the team
+func (pc *ProjectCollaborator) IsAccepted() bool {
+	return pc.Status == CollaboratorAccepted
+}
+
+// CollaboratorRole returns the user's role on the project. The project's user is always
+// an owner; other members are only known when the accepted collaborators were preloaded.
+func (p *Project) CollaboratorRole(userID uuid.UUID) (CollaboratorRole, bool) {
+	if p.UserID == userID {
+		return CollaboratorOwner, true
+	}
+	for _, collaborator := range p.Collaborators {
+		if collaborator.UserID == userID && collaborator.IsAccepted() {
+			return collaborator.Role, true
+		}
+	}
+	return "", false
+}
+
+// CollaboratorResponse is a team member as shown on the project
+type CollaboratorResponse struct {
+	UserID    uuid.UUID        `json:"userId"`
+	Username  string           `json:"username"`
+	Name      string           `json:"name"`
+	AvatarURL *string          `json:"avatarUrl"`
+	Role      CollaboratorRole `json:"role"`
+}
+
+func (pc *ProjectCollaborator) ToResponse() CollaboratorResponse {
+	return CollaboratorResponse{
+		UserID:    pc.UserID,
+		Username:  pc.User.Username,
+		Name:      pc.User.Name,
+		AvatarURL: pc.User.AvatarURL,
+		Role:      pc.Role,
+	}
+}
+
+// CollaboratorDetailResponse adds the invitation state and revenue share for the team management view
+type CollaboratorDetailResponse struct {
+	CollaboratorResponse
+	ID           uuid.UUID          `json:"id"`
+	Status       CollaboratorStatus `json:"status"`
+	RevenueShare int                `json:"revenueShare"`
+	AcceptedAt   *time.Time         `json:"acceptedAt"`
+	CreatedAt    time.Time          `json:"invitedAt"`
+}
+
+func (pc *ProjectCollaborator) ToDetailResponse() CollaboratorDetailResponse {
+	return CollaboratorDetailResponse{
+		CollaboratorResponse: pc.ToResponse(),
+		ID:                   pc.ID,
+		Status:               pc.Status,
+		RevenueShare:         pc.RevenueShare,
+		AcceptedAt:           pc.AcceptedAt,
+		CreatedAt:            pc.CreatedAt,
+	}
+}
+
+// InvitationResponse is a pending invitation as seen by the invitee
+type InvitationResponse struct {
+	ID           uuid.UUID          `json:"id"`
+	ProjectID    uuid.UUID          `json:"projectId"`
+	ProjectTitle string             `json:"projectTitle"`
+	Role         CollaboratorRole   `json:"role"`
+	InvitedBy    *uuid.UUID         `json:"invitedBy"`
+	Status       CollaboratorStatus `json:"status"`
+	CreatedAt    time.Time          `json:"invitedAt"`
+}
+
+func (pc *ProjectCollaborator) ToInvitationResponse() InvitationResponse {
+	return InvitationResponse{
+		ID:           pc.ID,
+		ProjectID:    pc.ProjectID,
+		ProjectTitle: pc.Project.Title,
+		Role:         pc.Role,
+		InvitedBy:    pc.InvitedBy,
+		Status:       pc.Status,
+		CreatedAt:    pc.CreatedAt,
+	}
+}
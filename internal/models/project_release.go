@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProjectRelease is a version of a project's deliverables. Its files are kept in private
+// storage and handed out through short-lived signed download URLs.
+type ProjectRelease struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_project_releases_version" json:"projectId"`
+	Version   string     `gorm:"size:50;not null;uniqueIndex:idx_project_releases_version" json:"version"`
+	Notes     *string    `gorm:"type:text" json:"notes"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"createdBy"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	Assets []ReleaseAsset `gorm:"foreignKey:ReleaseID" json:"assets,omitempty"`
+}
+
+func (r *ProjectRelease) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// ReleaseAsset is an uploaded ZIP or PDF file of a release. StoredName is the file's
+// name in the private storage directory; FileName is the name the uploader gave it.
+type ReleaseAsset struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ReleaseID   uuid.UUID `gorm:"type:uuid;not null;index" json:"releaseId"`
+	FileName    string    `gorm:"size:255;not null" json:"fileName"`
+	StoredName  string    `gorm:"size:255;not null" json:"-"`
+	ContentType string    `gorm:"size:100;not null" json:"contentType"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Downloads   int       `gorm:"not null;default:0" json:"downloads"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	Release ProjectRelease `gorm:"foreignKey:ReleaseID" json:"-"`
+}
+
+func (a *ReleaseAsset) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+type ReleaseAssetResponse struct {
+	ID          uuid.UUID `json:"id"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	Downloads   int       `json:"downloads"`
+}
+
+// ProjectReleaseResponse for API response. Downloads adds up the downloads of all assets.
+type ProjectReleaseResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	Version   string                 `json:"version"`
+	Notes     *string                `json:"notes"`
+	Assets    []ReleaseAssetResponse `json:"assets"`
+	Downloads int                    `json:"downloads"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+func (r *ProjectRelease) ToResponse() ProjectReleaseResponse {
+	assets := make([]ReleaseAssetResponse, len(r.Assets))
+	downloads := 0
+	for i, asset := range r.Assets {
+		assets[i] = ReleaseAssetResponse{
+			ID:          asset.ID,
+			FileName:    asset.FileName,
+			ContentType: asset.ContentType,
+			Size:        asset.Size,
+			Downloads:   asset.Downloads,
+		}
+		downloads += asset.Downloads
+	}
+
+	return ProjectReleaseResponse{
+		ID:        r.ID,
+		Version:   r.Version,
+		Notes:     r.Notes,
+		Assets:    assets,
+		Downloads: downloads,
+		CreatedAt: r.CreatedAt,
+	}
+}
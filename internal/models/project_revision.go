@@ -0,0 +1,196 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ProjectRevision is the state of a project after an edit. The first revision of a
+// project is its state when it was created, or before its first tracked edit.
+// EditorID is nil for that baseline when the project predates revision history.
+type ProjectRevision struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID     uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_project_revisions_number" json:"projectId"`
+	Number        int             `gorm:"not null;uniqueIndex:idx_project_revisions_number" json:"number"`
+	EditorID      *uuid.UUID      `gorm:"type:uuid" json:"editorId"`
+	ChangedFields pq.StringArray  `gorm:"type:text[]" json:"changedFields"`
+	RestoredFrom  *int            `json:"restoredFrom"`
+	Snapshot      ProjectSnapshot `gorm:"type:jsonb;not null" json:"snapshot"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	Editor *User `gorm:"foreignKey:EditorID" json:"-"`
+}
+
+func (r *ProjectRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// ProjectSnapshot holds the fields an author edits. Status and slug are left out because
+// they follow the publishing workflow and the title.
+type ProjectSnapshot struct {
+	Title        string      `json:"title"`
+	Description  *string     `json:"description"`
+	ThumbnailURL *string     `json:"thumbnailUrl"`
+	Images       []string    `json:"images"`
+	TechStack    []string    `json:"techStack"`
+	GithubURL    *string     `json:"githubUrl"`
+	DemoURL      *string     `json:"demoUrl"`
+	Type         ProjectType `json:"type"`
+	Price        int         `json:"price"`
+	CategoryID   *uuid.UUID  `json:"categoryId"`
+	PublishAt    *time.Time  `json:"publishAt"`
+}
+
+// Value stores the snapshot as JSON
+func (s ProjectSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan reads a snapshot stored as JSON
+func (s *ProjectSnapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return errors.New("unsupported project snapshot value")
+	}
+}
+
+// Snapshot captures the editable fields of the project. Images must be preloaded in sort order.
+func (p *Project) Snapshot() ProjectSnapshot {
+	images := make([]string, len(p.Images))
+	for i, img := range p.Images {
+		images[i] = img.ImageURL
+	}
+
+	return ProjectSnapshot{
+		Title:        p.Title,
+		Description:  p.Description,
+		ThumbnailURL: p.ThumbnailURL,
+		Images:       images,
+		TechStack:    append([]string{}, p.TechStack...),
+		GithubURL:    p.GithubURL,
+		DemoURL:      p.DemoURL,
+		Type:         p.Type,
+		Price:        p.Price,
+		CategoryID:   p.CategoryID,
+		PublishAt:    p.PublishAt,
+	}
+}
+
+// FieldChange is one field that differs between two snapshots
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff lists the fields that differ from the other snapshot, named as in the JSON
+// representation and in declaration order
+func (s ProjectSnapshot) Diff(from ProjectSnapshot) []FieldChange {
+	changes := []FieldChange{}
+
+	to := reflect.ValueOf(s)
+	old := reflect.ValueOf(from)
+	for i := 0; i < to.NumField(); i++ {
+		field := to.Type().Field(i)
+		newValue, oldValue := diffValue(to.Field(i)), diffValue(old.Field(i))
+		if reflect.DeepEqual(newValue, oldValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field: field.Tag.Get("json"),
+			From:  oldValue,
+			To:    newValue,
+		})
+	}
+	return changes
+}
+
+// diffValue dereferences pointers and treats nil and empty slices alike, so a field
+// only counts as changed when its content did
+func diffValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if t, ok := v.Interface().(*time.Time); ok {
+			return t.UTC()
+		}
+		return v.Elem().Interface()
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return []string{}
+		}
+	}
+	return v.Interface()
+}
+
+// ChangedFields names the fields that differ from the other snapshot
+func (s ProjectSnapshot) ChangedFields(from ProjectSnapshot) []string {
+	changes := s.Diff(from)
+	fields := make([]string, len(changes))
+	for i, change := range changes {
+		fields[i] = change.Field
+	}
+	return fields
+}
+
+// Apply copies the snapshot onto the project. Images are replaced by the caller.
+func (s ProjectSnapshot) Apply(p *Project) {
+	p.Title = s.Title
+	p.Description = s.Description
+	p.ThumbnailURL = s.ThumbnailURL
+	p.TechStack = pq.StringArray(append([]string{}, s.TechStack...))
+	p.GithubURL = s.GithubURL
+	p.DemoURL = s.DemoURL
+	p.Type = s.Type
+	p.Price = s.Price
+	p.CategoryID = s.CategoryID
+	p.PublishAt = s.PublishAt
+}
+
+// ProjectRevisionResponse describes a revision. The snapshot is only included for a single revision.
+type ProjectRevisionResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	Number        int                 `json:"number"`
+	Editor        *PublicUserResponse `json:"editor"`
+	ChangedFields []string            `json:"changedFields"`
+	RestoredFrom  *int                `json:"restoredFrom,omitempty"`
+	Snapshot      *ProjectSnapshot    `json:"snapshot,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+}
+
+func (r *ProjectRevision) ToResponse(includeSnapshot bool) ProjectRevisionResponse {
+	response := ProjectRevisionResponse{
+		ID:            r.ID,
+		Number:        r.Number,
+		ChangedFields: append([]string{}, r.ChangedFields...),
+		RestoredFrom:  r.RestoredFrom,
+		CreatedAt:     r.CreatedAt,
+	}
+	// The editor is only known when the relation was preloaded
+	if r.Editor != nil {
+		editor := r.Editor.ToPublicResponse()
+		response.Editor = &editor
+	}
+	if includeSnapshot {
+		snapshot := r.Snapshot
+		response.Snapshot = &snapshot
+	}
+	return response
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is a server-side login session. Each session holds the hash of the
+// refresh token that is currently valid for it; the token is rotated on every
+// refresh and the whole session is revoked when an old token is replayed.
+// ImpersonatedBy is set on short-lived sessions an admin uses to act as the user.
+type Session struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	RefreshTokenHash string     `gorm:"size:64;not null" json:"-"`
+	UserAgent        *string    `gorm:"type:text" json:"userAgent"`
+	IPAddress        *string    `gorm:"size:45" json:"ipAddress"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	LastUsedAt       time.Time  `gorm:"autoCreateTime" json:"lastUsedAt"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	ImpersonatedBy   *uuid.UUID `gorm:"type:uuid" json:"-"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the session can still be used
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// SessionResponse describes a session for the active sessions list
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  *string   `json:"userAgent"`
+	IPAddress  *string   `json:"ipAddress"`
+	Current    bool      `json:"current"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (s *Session) ToResponse(currentSessionID uuid.UUID) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		Current:    s.ID == currentSessionID,
+		LastUsedAt: s.LastUsedAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SlugResourceType string
+
+const (
+	SlugResourceUser    SlugResourceType = "user"
+	SlugResourceProject SlugResourceType = "project"
+)
+
+const (
+	UsernameMinLength    = 3
+	UsernameMaxLength    = 30
+	ProjectSlugMaxLength = 120
+)
+
+// reservedUsernames would clash with frontend routes or impersonate the platform
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "moderator": true, "api": true, "me": true,
+	"u": true, "users": true, "projects": true, "articles": true, "settings": true,
+	"login": true, "register": true, "logout": true, "leaderboard": true, "support": true,
+	"campus-hub": true, "campus-project-hub": true,
+}
+
+// IsReservedUsername reports whether the name cannot be claimed by users. The "deleted-"
+// prefix is used for anonymized accounts.
+func IsReservedUsername(username string) bool {
+	return reservedUsernames[username] || strings.HasPrefix(username, "deleted-")
+}
+
+// SlugRedirect remembers a previous username or project slug so old URLs keep working.
+// Project slugs are scoped by their author's ID; usernames use uuid.Nil as scope.
+type SlugRedirect struct {
+	ID           uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ResourceType SlugResourceType `gorm:"size:20;not null;uniqueIndex:idx_slug_redirects_slug" json:"resourceType"`
+	ScopeID      uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_slug_redirects_slug" json:"scopeId"`
+	Slug         string           `gorm:"size:120;not null;uniqueIndex:idx_slug_redirects_slug" json:"slug"`
+	ResourceID   uuid.UUID        `gorm:"type:uuid;not null;index" json:"resourceId"`
+	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (r *SlugRedirect) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// UsernameTaken reports whether the username is used, reserved or still redirects to
+// another account. exceptUserID lets a user keep or reclaim their own names.
+func UsernameTaken(tx *gorm.DB, username string, exceptUserID uuid.UUID) (bool, error) {
+	if IsReservedUsername(username) {
+		return true, nil
+	}
+
+	var count int64
+	err := tx.Model(&User{}).Where("username = ? AND id <> ?", username, exceptUserID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = tx.Model(&SlugRedirect{}).
+		Where("resource_type = ? AND scope_id = ? AND slug = ? AND resource_id <> ?", SlugResourceUser, uuid.Nil, username, exceptUserID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ProjectSlugTaken reports whether the author already uses the slug for another project,
+// now or in a redirect
+func ProjectSlugTaken(tx *gorm.DB, userID uuid.UUID, slug string, exceptProjectID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.Model(&Project{}).Where("user_id = ? AND slug = ? AND id <> ?", userID, slug, exceptProjectID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = tx.Model(&SlugRedirect{}).
+		Where("resource_type = ? AND scope_id = ? AND slug = ? AND resource_id <> ?", SlugResourceProject, userID, slug, exceptProjectID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// availableSlug returns base, or base with the first numeric suffix that is not taken
+func availableSlug(base string, maxLen int, taken func(candidate string) (bool, error)) (string, error) {
+	for n := 1; n <= 100; n++ {
+		candidate := base
+		if n > 1 {
+			suffix := fmt.Sprintf("-%d", n)
+			candidate = strings.TrimRight(base[:min(len(base), maxLen-len(suffix))], "-") + suffix
+		}
+
+		isTaken, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !isTaken {
+			return candidate, nil
+		}
+	}
+
+	// Very common names fall back to a random suffix instead of counting further
+	suffix := "-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+	return strings.TrimRight(base[:min(len(base), maxLen-len(suffix))], "-") + suffix, nil
+}
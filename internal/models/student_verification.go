@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudentVerification is a pending campus email confirmation. A user has at most one.
+type StudentVerification struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"userId"`
+	Email        string    `gorm:"not null;size:255" json:"email"`
+	UniversityID uuid.UUID `gorm:"type:uuid;not null" json:"universityId"`
+	CodeHash     string    `gorm:"not null;size:64" json:"-"`
+	Attempts     int       `gorm:"default:0" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null" json:"expiresAt"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	User       User       `gorm:"foreignKey:UserID" json:"-"`
+	University University `gorm:"foreignKey:UniversityID" json:"-"`
+}
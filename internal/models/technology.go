@@ -0,0 +1,72 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Technology is a canonical entry of the tech stack taxonomy. Key is the normalized name
+// and Aliases holds the normalized spellings that also mean this technology, so "react.js"
+// and "ReactJS" both find React.
+type Technology struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name      string         `gorm:"not null;size:100" json:"name"`
+	Key       string         `gorm:"uniqueIndex;not null;size:100" json:"-"`
+	Slug      string         `gorm:"uniqueIndex;not null;size:100" json:"slug"`
+	Aliases   pq.StringArray `gorm:"type:text[]" json:"aliases"`
+	IconURL   *string        `gorm:"type:text" json:"iconUrl"`
+	Color     *string        `gorm:"size:20" json:"color"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	// Computed field (not stored in DB)
+	ProjectCount int `gorm:"-" json:"projectCount"`
+}
+
+func (t *Technology) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TechnologyKey normalizes a technology name for matching: lower case, keeping only
+// letters, digits, "+" and "#" so that "C++" and "C#" stay apart. The migration that
+// created the taxonomy uses the same rule in SQL.
+func TechnologyKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '+' || r == '#' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// TechnologySlug derives the URL slug from a key, spelling out "+" and "#"
+func TechnologySlug(key string) string {
+	return strings.NewReplacer("+", "p", "#", "sharp").Replace(key)
+}
+
+// TechnologyResponse is a technology as shown on a project
+type TechnologyResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Slug    string    `json:"slug"`
+	IconURL *string   `json:"iconUrl"`
+	Color   *string   `json:"color"`
+}
+
+func (t *Technology) ToResponse() TechnologyResponse {
+	return TechnologyResponse{
+		ID:      t.ID,
+		Name:    t.Name,
+		Slug:    t.Slug,
+		IconURL: t.IconURL,
+		Color:   t.Color,
+	}
+}
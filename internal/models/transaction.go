@@ -28,9 +28,10 @@ type Transaction struct {
 	UpdatedAt             time.Time         `gorm:"autoUpdateTime" json:"updatedAt"`
 
 	// Relationships
-	Project Project `gorm:"foreignKey:ProjectID" json:"project,omitempty"`
-	Buyer   User    `gorm:"foreignKey:BuyerID" json:"buyer,omitempty"`
-	Seller  User    `gorm:"foreignKey:SellerID" json:"seller,omitempty"`
+	Project Project            `gorm:"foreignKey:ProjectID" json:"project,omitempty"`
+	Buyer   User               `gorm:"foreignKey:BuyerID" json:"buyer,omitempty"`
+	Seller  User               `gorm:"foreignKey:SellerID" json:"seller,omitempty"`
+	Shares  []TransactionShare `gorm:"foreignKey:TransactionID" json:"shares,omitempty"`
 }
 
 func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
@@ -40,6 +41,23 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// TransactionShare is the part of a sale paid to one of the project's owners. The split
+// is fixed when the purchase starts, so later changes to the team do not affect it.
+type TransactionShare struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;index" json:"transactionId"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Percent       int       `gorm:"not null" json:"percent"`
+	Amount        int       `gorm:"not null" json:"amount"`
+}
+
+func (s *TransactionShare) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
 type TransactionResponse struct {
 	ID           uuid.UUID         `json:"id"`
 	ProjectID    uuid.UUID         `json:"projectId"`
@@ -47,10 +65,22 @@ type TransactionResponse struct {
 	BuyerName    string            `json:"buyerName"`
 	Amount       int               `json:"amount"`
 	Status       TransactionStatus `json:"status"`
+	Shares       []ShareResponse   `json:"shares,omitempty"`
 	CreatedAt    time.Time         `json:"createdAt"`
 }
 
+type ShareResponse struct {
+	UserID  uuid.UUID `json:"userId"`
+	Percent int       `json:"percent"`
+	Amount  int       `json:"amount"`
+}
+
 func (t *Transaction) ToResponse() TransactionResponse {
+	var shares []ShareResponse
+	for _, share := range t.Shares {
+		shares = append(shares, ShareResponse{UserID: share.UserID, Percent: share.Percent, Amount: share.Amount})
+	}
+
 	return TransactionResponse{
 		ID:           t.ID,
 		ProjectID:    t.ProjectID,
@@ -58,6 +88,7 @@ func (t *Transaction) ToResponse() TransactionResponse {
 		BuyerName:    t.Buyer.Name,
 		Amount:       t.Amount,
 		Status:       t.Status,
+		Shares:       shares,
 		CreatedAt:    t.CreatedAt,
 	}
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// University is the normalized record that campus email domains map to
+type University struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name      string         `gorm:"not null;size:255" json:"name"`
+	Slug      string         `gorm:"uniqueIndex;not null;size:255" json:"slug"`
+	Domains   pq.StringArray `gorm:"type:text[]" json:"domains"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (u *University) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+// UniversitySummary is embedded in user responses
+type UniversitySummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (u *University) ToSummary() *UniversitySummary {
+	return &UniversitySummary{ID: u.ID, Name: u.Name}
+}
+
+// EmailDomainCandidates returns the domain of an email and its parent domains,
+// so "mhs.ui.ac.id" also matches a university registered with "ui.ac.id"
+func EmailDomainCandidates(email string) []string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	var candidates []string
+	for strings.Contains(domain, ".") {
+		candidates = append(candidates, domain)
+		domain = domain[strings.Index(domain, ".")+1:]
+	}
+	return candidates
+}
@@ -1,8 +1,10 @@
 package models
 
 import (
+	"strings"
 	"time"
 
+	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -19,73 +21,214 @@ const (
 const (
 	StatusActive  UserStatus = "active"
 	StatusBlocked UserStatus = "blocked"
+	// StatusDeleted marks an anonymized account that is only kept for foreign keys
+	StatusDeleted UserStatus = "deleted"
 )
 
+// DeletedUserName replaces the name of anonymized accounts
+const DeletedUserName = "Pengguna terhapus"
+
 type User struct {
-	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Email         string     `gorm:"uniqueIndex;not null;size:255" json:"email"`
-	PasswordHash  *string    `gorm:"size:255" json:"-"`
-	Name          string     `gorm:"not null;size:255" json:"name"`
-	AvatarURL     *string    `gorm:"type:text" json:"avatarUrl"`
-	University    *string    `gorm:"size:255" json:"university"`
-	Major         *string    `gorm:"size:255" json:"major"`
-	Bio           *string    `gorm:"type:text" json:"bio"`
-	Phone         *string    `gorm:"size:20" json:"phone"`
-	Role          UserRole   `gorm:"size:20;default:'user'" json:"role"`
-	Status        UserStatus `gorm:"size:20;default:'active'" json:"status"`
-	TotalExp      int        `gorm:"default:0" json:"totalExp"`
-	OAuthProvider *string    `gorm:"column:oauth_provider;size:20" json:"oauthProvider,omitempty"`
-	OAuthID       *string    `gorm:"column:oauth_id;size:255" json:"-"`
-	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+	ID                   uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Email                string     `gorm:"uniqueIndex;not null;size:255" json:"email"`
+	Username             string     `gorm:"uniqueIndex;not null;size:30" json:"username"`
+	PasswordHash         *string    `gorm:"size:255" json:"-"`
+	Name                 string     `gorm:"not null;size:255" json:"name"`
+	AvatarURL            *string    `gorm:"type:text" json:"avatarUrl"`
+	University           *string    `gorm:"size:255" json:"university"`
+	Major                *string    `gorm:"size:255" json:"major"`
+	Bio                  *string    `gorm:"type:text" json:"bio"`
+	Phone                *string    `gorm:"size:20" json:"phone"`
+	Role                 UserRole   `gorm:"size:20;default:'user'" json:"role"`
+	Status               UserStatus `gorm:"size:20;default:'active'" json:"status"`
+	TotalExp             int        `gorm:"default:0" json:"totalExp"`
+	EmailVerifiedAt      *time.Time `json:"emailVerifiedAt"`
+	TOTPSecret           *string    `gorm:"column:totp_secret;size:64" json:"-"`
+	TOTPEnabledAt        *time.Time `gorm:"column:totp_enabled_at" json:"-"`
+	TOTPLastStep         int64      `gorm:"column:totp_last_step;default:0" json:"-"`
+	CampusEmail          *string    `gorm:"size:255" json:"-"`
+	VerifiedUniversityID *uuid.UUID `gorm:"type:uuid" json:"verifiedUniversityId"`
+	StudentVerifiedAt    *time.Time `json:"studentVerifiedAt"`
+	DeletionScheduledAt  *time.Time `json:"-"`
+	ShowEmail            bool       `gorm:"default:false" json:"-"`
+	ShowPhone            bool       `gorm:"default:false" json:"-"`
+	ShowUniversity       bool       `gorm:"default:true" json:"-"`
+	HideFromLeaderboard  bool       `gorm:"default:false" json:"-"`
+	AnonymizedAt         *time.Time `json:"-"`
+	CreatedAt            time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt            time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
 
 	// Relationships
-	Projects      []Project `gorm:"foreignKey:UserID" json:"projects,omitempty"`
-	Articles      []Article `gorm:"foreignKey:UserID" json:"articles,omitempty"`
-	Comments      []Comment `gorm:"foreignKey:UserID" json:"comments,omitempty"`
-	LikedProjects []Project `gorm:"many2many:project_likes" json:"likedProjects,omitempty"`
+	Projects           []Project      `gorm:"foreignKey:UserID" json:"projects,omitempty"`
+	Articles           []Article      `gorm:"foreignKey:UserID" json:"articles,omitempty"`
+	Comments           []Comment      `gorm:"foreignKey:UserID" json:"comments,omitempty"`
+	LikedProjects      []Project      `gorm:"many2many:project_likes" json:"likedProjects,omitempty"`
+	Identities         []UserIdentity `gorm:"foreignKey:UserID" json:"identities,omitempty"`
+	VerifiedUniversity *University    `gorm:"foreignKey:VerifiedUniversityID" json:"verifiedUniversity,omitempty"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == uuid.Nil {
 		u.ID = uuid.New()
 	}
+	if u.Username == "" {
+		username, err := GenerateUsername(tx.Session(&gorm.Session{NewDB: true}), u.Name, u.ID)
+		if err != nil {
+			return err
+		}
+		u.Username = username
+	}
 	return nil
 }
 
+// GenerateUsername derives a free username from the user's display name
+func GenerateUsername(tx *gorm.DB, name string, userID uuid.UUID) (string, error) {
+	base := utils.Slugify(name, UsernameMaxLength)
+	if len(base) < UsernameMinLength {
+		base = strings.TrimLeft(base+"-user", "-")
+	}
+	return availableSlug(base, UsernameMaxLength, func(candidate string) (bool, error) {
+		return UsernameTaken(tx, candidate, userID)
+	})
+}
+
+// IsEmailVerified reports whether the user proved ownership of their email
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsTwoFactorEnabled reports whether the user must provide a TOTP code on login
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil && u.TOTPSecret != nil
+}
+
+// IsDeleted reports whether the account has been anonymized
+func (u *User) IsDeleted() bool {
+	return u.Status == StatusDeleted
+}
+
+// IsVerifiedStudent reports whether the user confirmed a campus email of a known university
+func (u *User) IsVerifiedStudent() bool {
+	return u.StudentVerifiedAt != nil && u.VerifiedUniversityID != nil
+}
+
 // UserResponse is the safe response without sensitive data
 type UserResponse struct {
-	ID         uuid.UUID  `json:"id"`
-	Email      string     `json:"email"`
-	Name       string     `json:"name"`
-	AvatarURL  *string    `json:"avatarUrl"`
-	University *string    `json:"university"`
-	Major      *string    `json:"major"`
-	Bio        *string    `json:"bio"`
-	Phone      *string    `json:"phone"`
-	Role       UserRole   `json:"role"`
-	Status     UserStatus `json:"status"`
-	TotalExp   int        `json:"totalExp"`
-	Level      int        `json:"level"`
-	CreatedAt  time.Time  `json:"createdAt"`
+	ID                 uuid.UUID          `json:"id"`
+	Email              string             `json:"email"`
+	Username           string             `json:"username"`
+	Name               string             `json:"name"`
+	AvatarURL          *string            `json:"avatarUrl"`
+	University         *string            `json:"university"`
+	Major              *string            `json:"major"`
+	Bio                *string            `json:"bio"`
+	Phone              *string            `json:"phone"`
+	Role               UserRole           `json:"role"`
+	Status             UserStatus         `json:"status"`
+	TotalExp           int                `json:"totalExp"`
+	Level              int                `json:"level"`
+	EmailVerified      bool               `json:"emailVerified"`
+	TwoFactorEnabled   bool               `json:"twoFactorEnabled"`
+	VerifiedStudent    bool               `json:"verifiedStudent"`
+	VerifiedUniversity *UniversitySummary `json:"verifiedUniversity,omitempty"`
+	Privacy            PrivacySettings    `json:"privacy"`
+	CreatedAt          time.Time          `json:"createdAt"`
+}
+
+// PrivacySettings controls which profile fields other users can see
+type PrivacySettings struct {
+	ShowEmail           bool `json:"showEmail"`
+	ShowPhone           bool `json:"showPhone"`
+	ShowUniversity      bool `json:"showUniversity"`
+	HideFromLeaderboard bool `json:"hideFromLeaderboard"`
+}
+
+// PublicUserResponse is the profile shown to other users. Email, phone and university
+// are only included when the user chose to show them.
+type PublicUserResponse struct {
+	ID                 uuid.UUID          `json:"id"`
+	Username           string             `json:"username"`
+	Name               string             `json:"name"`
+	AvatarURL          *string            `json:"avatarUrl"`
+	Bio                *string            `json:"bio"`
+	Email              *string            `json:"email,omitempty"`
+	Phone              *string            `json:"phone,omitempty"`
+	University         *string            `json:"university,omitempty"`
+	Major              *string            `json:"major,omitempty"`
+	Role               UserRole           `json:"role"`
+	TotalExp           int                `json:"totalExp"`
+	Level              int                `json:"level"`
+	VerifiedStudent    bool               `json:"verifiedStudent"`
+	VerifiedUniversity *UniversitySummary `json:"verifiedUniversity,omitempty"`
+	CreatedAt          time.Time          `json:"createdAt"`
 }
 
 func (u *User) ToResponse() UserResponse {
-	return UserResponse{
-		ID:         u.ID,
-		Email:      u.Email,
-		Name:       u.Name,
-		AvatarURL:  u.AvatarURL,
-		University: u.University,
-		Major:      u.Major,
-		Bio:        u.Bio,
-		Phone:      u.Phone,
-		Role:       u.Role,
-		Status:     u.Status,
-		TotalExp:   u.TotalExp,
-		Level:      GetLevelFromExp(u.TotalExp),
-		CreatedAt:  u.CreatedAt,
+	response := UserResponse{
+		ID:               u.ID,
+		Email:            u.Email,
+		Username:         u.Username,
+		Name:             u.Name,
+		AvatarURL:        u.AvatarURL,
+		University:       u.University,
+		Major:            u.Major,
+		Bio:              u.Bio,
+		Phone:            u.Phone,
+		Role:             u.Role,
+		Status:           u.Status,
+		TotalExp:         u.TotalExp,
+		Level:            GetLevelFromExp(u.TotalExp),
+		EmailVerified:    u.IsEmailVerified(),
+		TwoFactorEnabled: u.IsTwoFactorEnabled(),
+		VerifiedStudent:  u.IsVerifiedStudent(),
+		Privacy:          u.PrivacySettings(),
+		CreatedAt:        u.CreatedAt,
+	}
+	// The university name is only known when the relation was preloaded
+	if u.IsVerifiedStudent() && u.VerifiedUniversity != nil {
+		response.VerifiedUniversity = u.VerifiedUniversity.ToSummary()
+	}
+	return response
+}
+
+// PrivacySettings returns the user's profile visibility choices
+func (u *User) PrivacySettings() PrivacySettings {
+	return PrivacySettings{
+		ShowEmail:           u.ShowEmail,
+		ShowPhone:           u.ShowPhone,
+		ShowUniversity:      u.ShowUniversity,
+		HideFromLeaderboard: u.HideFromLeaderboard,
+	}
+}
+
+// ToPublicResponse projects the user for anyone other than the owner and admins
+func (u *User) ToPublicResponse() PublicUserResponse {
+	response := PublicUserResponse{
+		ID:              u.ID,
+		Username:        u.Username,
+		Name:            u.Name,
+		AvatarURL:       u.AvatarURL,
+		Bio:             u.Bio,
+		Role:            u.Role,
+		TotalExp:        u.TotalExp,
+		Level:           GetLevelFromExp(u.TotalExp),
+		VerifiedStudent: u.IsVerifiedStudent(),
+		CreatedAt:       u.CreatedAt,
+	}
+	if u.ShowEmail {
+		response.Email = &u.Email
+	}
+	if u.ShowPhone {
+		response.Phone = u.Phone
+	}
+	if u.ShowUniversity {
+		response.University = u.University
+		response.Major = u.Major
+		if u.IsVerifiedStudent() && u.VerifiedUniversity != nil {
+			response.VerifiedUniversity = u.VerifiedUniversity.ToSummary()
+		}
 	}
+	return response
 }
 
 // Gamification helpers
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links an external login provider account to a user.
+// A user can have any number of identities, one per provider account.
+type UserIdentity struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Provider       string    `gorm:"size:50;not null;uniqueIndex:idx_user_identities_provider_subject" json:"provider"`
+	ProviderUserID string    `gorm:"size:255;not null;uniqueIndex:idx_user_identities_provider_subject" json:"-"`
+	Email          *string   `gorm:"size:255" json:"email"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	LastUsedAt     time.Time `gorm:"autoCreateTime" json:"lastUsedAt"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
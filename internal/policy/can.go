@@ -0,0 +1,94 @@
+package policy
+
+import "github.com/campus-project-hub/api/internal/models"
+
+// Action is something a user does with a single resource
+type Action string
+
+const (
+	// ActionRead covers what is hidden from the public: unpublished projects and
+	// articles, and the private fields of a user profile
+	ActionRead   Action = "read"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	// ActionManage covers a project's team and revenue split
+	ActionManage Action = "manage"
+)
+
+// Can reports whether the user may perform the action on the resource. Owners may
+// always read, update and delete their own resources; everyone else needs the
+// matching ".any" permission. Project collaborators act according to their role.
+// Unknown resources and actions are denied.
+func Can(user *models.User, action Action, resource interface{}) bool {
+	return Owns(user, action, resource) || Has(user, AnyPermission(action, resource))
+}
+
+// Owns reports whether the user may perform the action on the resource as its owner,
+// or for projects as a collaborator, without any staff permission
+func Owns(user *models.User, action Action, resource interface{}) bool {
+	if user == nil {
+		return false
+	}
+
+	switch r := resource.(type) {
+	case *models.Project:
+		role, ok := r.CollaboratorRole(user.ID)
+		return ok && collaboratorCan(role, action)
+	case *models.Article:
+		return r.UserID == user.ID && action != ActionManage
+	case *models.Comment:
+		// Project owners moderate the comments on their own projects
+		return action == ActionDelete && (r.UserID == user.ID || r.Project.UserID == user.ID)
+	case *models.User:
+		return r.ID == user.ID && action != ActionManage
+	}
+
+	return false
+}
+
+// AnyPermission returns the staff permission that allows the action on resources of other
+// users, or "" when there is none
+func AnyPermission(action Action, resource interface{}) Permission {
+	switch resource.(type) {
+	case *models.Project:
+		if action == ActionManage {
+			return ProjectUpdateAny
+		}
+		return anyPermission(action, ProjectReadAny, ProjectUpdateAny, ProjectDeleteAny)
+	case *models.Article:
+		return anyPermission(action, ArticleReadAny, ArticleUpdateAny, ArticleDeleteAny)
+	case *models.Comment:
+		if action == ActionDelete {
+			return CommentDeleteAny
+		}
+	case *models.User:
+		return anyPermission(action, UserReadPrivate, UserUpdateAny, UserDelete)
+	}
+	return ""
+}
+
+func anyPermission(action Action, readAny, updateAny, deleteAny Permission) Permission {
+	switch action {
+	case ActionRead:
+		return readAny
+	case ActionUpdate:
+		return updateAny
+	case ActionDelete:
+		return deleteAny
+	}
+	return ""
+}
+
+// collaboratorCan maps project roles to actions. Every member sees the project while it is
+// unpublished, maintainers also edit it, and owners delete it and manage the team.
+func collaboratorCan(role models.CollaboratorRole, action Action) bool {
+	switch action {
+	case ActionRead:
+		return true
+	case ActionUpdate:
+		return role == models.CollaboratorOwner || role == models.CollaboratorMaintainer
+	case ActionDelete, ActionManage:
+		return role == models.CollaboratorOwner
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// Package policy maps roles to named permissions and decides what a user may do
+// with a resource. Handlers and middleware ask this package instead of comparing roles.
+package policy
+
+import (
+	"slices"
+
+	"github.com/campus-project-hub/api/internal/models"
+)
+
+// Permission names an action a role may perform. Permissions ending in ".any"
+// extend an action that owners can always perform to resources of other users.
+type Permission string
+
+const (
+	ProjectReadAny   Permission = "project.read.any"
+	ProjectUpdateAny Permission = "project.update.any"
+	ProjectDeleteAny Permission = "project.delete.any"
+	ProjectBlock     Permission = "project.block"
+	ProjectReview    Permission = "project.review"
+
+	ArticleReadAny   Permission = "article.read.any"
+	ArticleUpdateAny Permission = "article.update.any"
+	ArticleDeleteAny Permission = "article.delete.any"
+
+	// OwnershipTransferForce moves projects and articles without the recipient's consent
+	OwnershipTransferForce Permission = "ownership.transfer.force"
+
+	CommentDeleteAny Permission = "comment.delete.any"
+
+	UserList           Permission = "user.list"
+	UserReadPrivate    Permission = "user.read.private"
+	UserUpdateAny      Permission = "user.update.any"
+	UserDelete         Permission = "user.delete"
+	UserBlock          Permission = "user.block"
+	UserRevokeSessions Permission = "user.sessions.revoke"
+	UserClearLockout   Permission = "user.lockout.clear"
+	UserImpersonate    Permission = "user.impersonate"
+
+	TransactionListAny Permission = "transaction.list.any"
+	CategoryManage     Permission = "category.manage"
+	TechnologyManage   Permission = "technology.manage"
+	UniversityManage   Permission = "university.manage"
+	RoleView           Permission = "role.view"
+	AuditLogView       Permission = "audit.view"
+)
+
+// Permissions lists every permission in display order
+var Permissions = []Permission{
+	ProjectReadAny,
+	ProjectUpdateAny,
+	ProjectDeleteAny,
+	ProjectBlock,
+	ProjectReview,
+	ArticleReadAny,
+	ArticleUpdateAny,
+	ArticleDeleteAny,
+	OwnershipTransferForce,
+	CommentDeleteAny,
+	UserList,
+	UserReadPrivate,
+	UserUpdateAny,
+	UserDelete,
+	UserBlock,
+	UserRevokeSessions,
+	UserClearLockout,
+	UserImpersonate,
+	TransactionListAny,
+	CategoryManage,
+	TechnologyManage,
+	UniversityManage,
+	RoleView,
+	AuditLogView,
+}
+
+// Roles lists every role in display order
+var Roles = []models.UserRole{models.RoleUser, models.RoleModerator, models.RoleAdmin}
+
+// rolePermissions is the role to permission matrix. Regular users only act on their own resources.
+var rolePermissions = map[models.UserRole][]Permission{
+	models.RoleUser: {},
+	models.RoleModerator: {
+		ProjectReadAny,
+		ProjectBlock,
+		ProjectReview,
+		ArticleReadAny,
+		CommentDeleteAny,
+		UserBlock,
+	},
+	models.RoleAdmin: Permissions,
+}
+
+// RoleHas reports whether the role grants the permission
+func RoleHas(role models.UserRole, permission Permission) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// Has reports whether the user's role grants the permission. A nil user has no permissions.
+func Has(user *models.User, permission Permission) bool {
+	return user != nil && RoleHas(user.Role, permission)
+}
+
+// RolePermissions is one row of the permission matrix
+type RolePermissions struct {
+	Role        models.UserRole `json:"role"`
+	Permissions []Permission    `json:"permissions"`
+}
+
+// Matrix returns the permissions of every role
+func Matrix() []RolePermissions {
+	matrix := make([]RolePermissions, 0, len(Roles))
+	for _, role := range Roles {
+		matrix = append(matrix, RolePermissions{
+			Role:        role,
+			Permissions: slices.Clone(rolePermissions[role]),
+		})
+	}
+	return matrix
+}
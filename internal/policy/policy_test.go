@@ -0,0 +1,199 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func newTestUser(role models.UserRole) *models.User {
+	return &models.User{ID: uuid.New(), Role: role}
+}
+
+// testTeam is a project with a member of every collaborator role, plus users outside it
+type testTeam struct {
+	author, coOwner, maintainer, contributor, invited, stranger, moderator, admin *models.User
+	project                                                                       *models.Project
+}
+
+func newTestTeam() *testTeam {
+	team := &testTeam{
+		author:      newTestUser(models.RoleUser),
+		coOwner:     newTestUser(models.RoleUser),
+		maintainer:  newTestUser(models.RoleUser),
+		contributor: newTestUser(models.RoleUser),
+		invited:     newTestUser(models.RoleUser),
+		stranger:    newTestUser(models.RoleUser),
+		moderator:   newTestUser(models.RoleModerator),
+		admin:       newTestUser(models.RoleAdmin),
+	}
+	team.project = &models.Project{
+		ID:     uuid.New(),
+		UserID: team.author.ID,
+		Collaborators: []models.ProjectCollaborator{
+			{UserID: team.coOwner.ID, Role: models.CollaboratorOwner, Status: models.CollaboratorAccepted},
+			{UserID: team.maintainer.ID, Role: models.CollaboratorMaintainer, Status: models.CollaboratorAccepted},
+			{UserID: team.contributor.ID, Role: models.CollaboratorContributor, Status: models.CollaboratorAccepted},
+			// Invitations grant nothing until they are accepted
+			{UserID: team.invited.ID, Role: models.CollaboratorMaintainer, Status: models.CollaboratorPending},
+		},
+	}
+	return team
+}
+
+var allActions = []Action{ActionRead, ActionUpdate, ActionDelete, ActionManage}
+
+// allowed lists the actions a user may perform; the others must be denied
+type allowed map[Action]bool
+
+func allow(actions ...Action) allowed {
+	result := allowed{}
+	for _, action := range actions {
+		result[action] = true
+	}
+	return result
+}
+
+func TestCan(t *testing.T) {
+	team := newTestTeam()
+	article := &models.Article{ID: uuid.New(), UserID: team.author.ID}
+	comment := &models.Comment{ID: uuid.New(), UserID: team.contributor.ID, ProjectID: team.project.ID, Project: *team.project}
+	profile := team.stranger
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		resource interface{}
+		want     allowed
+	}{
+		{"project author", team.author, team.project, allow(ActionRead, ActionUpdate, ActionDelete, ActionManage)},
+		{"project co-owner", team.coOwner, team.project, allow(ActionRead, ActionUpdate, ActionDelete, ActionManage)},
+		{"project maintainer", team.maintainer, team.project, allow(ActionRead, ActionUpdate)},
+		{"project contributor", team.contributor, team.project, allow(ActionRead)},
+		{"pending invitee", team.invited, team.project, allow()},
+		{"project stranger", team.stranger, team.project, allow()},
+		{"moderator on project", team.moderator, team.project, allow(ActionRead)},
+		{"admin on project", team.admin, team.project, allow(ActionRead, ActionUpdate, ActionDelete, ActionManage)},
+
+		{"article author", team.author, article, allow(ActionRead, ActionUpdate, ActionDelete)},
+		{"article co-owner of a project", team.coOwner, article, allow()},
+		{"article stranger", team.stranger, article, allow()},
+		{"moderator on article", team.moderator, article, allow(ActionRead)},
+		{"admin on article", team.admin, article, allow(ActionRead, ActionUpdate, ActionDelete)},
+
+		{"comment author", team.contributor, comment, allow(ActionDelete)},
+		{"owner of the commented project", team.author, comment, allow(ActionDelete)},
+		{"co-owner of the commented project", team.coOwner, comment, allow()},
+		{"comment stranger", team.stranger, comment, allow()},
+		{"moderator on comment", team.moderator, comment, allow(ActionDelete)},
+		{"admin on comment", team.admin, comment, allow(ActionDelete)},
+
+		{"user themself", profile, profile, allow(ActionRead, ActionUpdate, ActionDelete)},
+		{"another user", team.author, profile, allow()},
+		{"moderator on user", team.moderator, profile, allow()},
+		{"admin on user", team.admin, profile, allow(ActionRead, ActionUpdate, ActionDelete)},
+
+		{"nobody", nil, team.project, allow()},
+		{"admin on unknown resource", team.admin, &models.Category{}, allow()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, action := range allActions {
+				if got := Can(tt.user, action, tt.resource); got != tt.want[action] {
+					t.Errorf("Can(%s) = %v, want %v", action, got, tt.want[action])
+				}
+			}
+		})
+	}
+}
+
+func TestOwns(t *testing.T) {
+	team := newTestTeam()
+	article := &models.Article{ID: uuid.New(), UserID: team.author.ID}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		resource interface{}
+		want     allowed
+	}{
+		{"project author", team.author, team.project, allow(ActionRead, ActionUpdate, ActionDelete, ActionManage)},
+		{"project maintainer", team.maintainer, team.project, allow(ActionRead, ActionUpdate)},
+		{"project contributor", team.contributor, team.project, allow(ActionRead)},
+		{"article author", team.author, article, allow(ActionRead, ActionUpdate, ActionDelete)},
+		// Staff permissions never count as ownership
+		{"moderator on project", team.moderator, team.project, allow()},
+		{"admin on project", team.admin, team.project, allow()},
+		{"admin on article", team.admin, article, allow()},
+		{"admin on user", team.admin, team.author, allow()},
+		{"nobody", nil, team.project, allow()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, action := range allActions {
+				if got := Owns(tt.user, action, tt.resource); got != tt.want[action] {
+					t.Errorf("Owns(%s) = %v, want %v", action, got, tt.want[action])
+				}
+			}
+		})
+	}
+}
+
+func TestAnyPermission(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource interface{}
+		want     map[Action]Permission
+	}{
+		{"project", &models.Project{}, map[Action]Permission{
+			ActionRead:   ProjectReadAny,
+			ActionUpdate: ProjectUpdateAny,
+			ActionDelete: ProjectDeleteAny,
+			ActionManage: ProjectUpdateAny,
+		}},
+		{"article", &models.Article{}, map[Action]Permission{
+			ActionRead:   ArticleReadAny,
+			ActionUpdate: ArticleUpdateAny,
+			ActionDelete: ArticleDeleteAny,
+		}},
+		{"comment", &models.Comment{}, map[Action]Permission{
+			ActionDelete: CommentDeleteAny,
+		}},
+		{"user", &models.User{}, map[Action]Permission{
+			ActionRead:   UserReadPrivate,
+			ActionUpdate: UserUpdateAny,
+			ActionDelete: UserDelete,
+		}},
+		{"unknown resource", &models.Category{}, map[Action]Permission{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, action := range append(allActions, Action("publish")) {
+				if got := AnyPermission(action, tt.resource); got != tt.want[action] {
+					t.Errorf("AnyPermission(%s) = %q, want %q", action, got, tt.want[action])
+				}
+			}
+		})
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	for _, permission := range Permissions {
+		if RoleHas(models.RoleUser, permission) {
+			t.Errorf("users have %s", permission)
+		}
+		if !RoleHas(models.RoleAdmin, permission) {
+			t.Errorf("admins lack %s", permission)
+		}
+	}
+
+	for _, permission := range []Permission{ProjectUpdateAny, ProjectDeleteAny, ArticleUpdateAny, UserUpdateAny, UserImpersonate, OwnershipTransferForce} {
+		if RoleHas(models.RoleModerator, permission) {
+			t.Errorf("moderators have %s", permission)
+		}
+	}
+}
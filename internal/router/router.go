@@ -1,7 +1,10 @@
 package router
 
 import (
+	"log"
+
 	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/handlers"
 	"github.com/campus-project-hub/api/internal/middleware"
 	"github.com/gin-gonic/gin"
 
@@ -15,12 +18,21 @@ import (
 func Setup(cfg *config.Config) *gin.Engine {
 	router := gin.Default()
 
+	// Forwarded client IPs are only believed from configured proxies, so that clients
+	// cannot pick the IP that login rate limits and audit logs see
+	if err := router.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
 	// Apply global middleware
 	router.Use(middleware.CORSMiddleware())
 
 	// Serve uploaded files
 	router.Static("/uploads", cfg.Upload.Dir)
 
+	// Public keys for verifying issued tokens
+	router.GET("/.well-known/jwks.json", handlers.NewAuthHandler().JWKS)
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
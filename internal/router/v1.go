@@ -3,6 +3,8 @@ package router
 import (
 	"github.com/campus-project-hub/api/internal/handlers"
 	"github.com/campus-project-hub/api/internal/middleware"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/policy"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,12 +14,24 @@ func SetupV1Routes(r *gin.Engine) {
 	authHandler := handlers.NewAuthHandler()
 	userHandler := handlers.NewUserHandler()
 	projectHandler := handlers.NewProjectHandler()
+	projectRevisionHandler := handlers.NewProjectRevisionHandler()
+	collaboratorHandler := handlers.NewCollaboratorHandler()
+	transferHandler := handlers.NewOwnershipTransferHandler()
+	releaseHandler := handlers.NewProjectReleaseHandler()
 	articleHandler := handlers.NewArticleHandler()
 	commentHandler := handlers.NewCommentHandler()
 	transactionHandler := handlers.NewTransactionHandler()
 	categoryHandler := handlers.NewCategoryHandler()
+	technologyHandler := handlers.NewTechnologyHandler()
+	searchHandler := handlers.NewSearchHandler()
 	uploadHandler := handlers.NewUploadHandler()
 	gamificationHandler := handlers.NewGamificationHandler()
+	twoFactorHandler := handlers.NewTwoFactorHandler()
+	identityHandler := handlers.NewIdentityHandler()
+	universityHandler := handlers.NewUniversityHandler()
+	accessTokenHandler := handlers.NewAccessTokenHandler()
+	roleHandler := handlers.NewRoleHandler()
+	auditLogHandler := handlers.NewAuditLogHandler()
 
 	// API v1 routes
 	api := r.Group("/api/v1")
@@ -37,28 +51,74 @@ func SetupV1Routes(r *gin.Engine) {
 			auth.GET("/google/callback", authHandler.GoogleCallback)
 			auth.GET("/github", authHandler.GitHubAuth)
 			auth.GET("/github/callback", authHandler.GitHubCallback)
+			auth.GET("/oidc", authHandler.OIDCProviders)
+			auth.GET("/oidc/:provider", authHandler.OIDCAuth)
+			auth.GET("/oidc/:provider/callback", authHandler.OIDCCallback)
+			auth.POST("/exchange", authHandler.Exchange)
+			auth.POST("/verify-email", authHandler.VerifyEmail)
+			auth.POST("/forgot-password", authHandler.ForgotPassword)
+			auth.POST("/reset-password", authHandler.ResetPassword)
+			auth.POST("/2fa/verify", twoFactorHandler.Verify)
 
 			// Protected auth routes
 			auth.Use(middleware.AuthMiddleware())
-			auth.GET("/me", authHandler.GetMe)
+			auth.GET("/me", middleware.RequireScope(models.ScopeRead), authHandler.GetMe)
+
+			// Account management needs an interactive login and is closed to impersonation
+			auth.Use(middleware.RequireSessionAuth())
 			auth.POST("/logout", authHandler.Logout)
+			auth.Use(middleware.DenyImpersonation())
+			auth.POST("/resend-verification", authHandler.ResendVerification)
+			auth.POST("/change-password", authHandler.ChangePassword)
+			auth.POST("/2fa/setup", twoFactorHandler.Setup)
+			auth.POST("/2fa/enable", twoFactorHandler.Enable)
+			auth.POST("/2fa/disable", twoFactorHandler.Disable)
+			auth.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			auth.GET("/identities", identityHandler.List)
+			auth.POST("/identities/link/:provider", identityHandler.Link)
+			auth.DELETE("/identities/:id", identityHandler.Unlink)
+			auth.GET("/sessions", authHandler.ListSessions)
+			auth.DELETE("/sessions", authHandler.RevokeOtherSessions)
+			auth.DELETE("/sessions/:id", authHandler.RevokeSession)
+			auth.GET("/tokens", accessTokenHandler.List)
+			auth.POST("/tokens", accessTokenHandler.Create)
+			auth.DELETE("/tokens/:id", accessTokenHandler.Revoke)
 		}
 
 		// User routes
 		users := api.Group("/users")
 		{
 			users.GET("/leaderboard", userHandler.Leaderboard)
-			users.GET("/:id", userHandler.Get)
+			users.GET("/:id", middleware.OptionalAuthMiddleware(), userHandler.Get)
 
 			// Protected user routes
-			users.Use(middleware.AuthMiddleware())
+			users.Use(middleware.AuthMiddleware(), middleware.RequireSessionAuth())
 			users.PUT("/:id", userHandler.Update)
+			users.POST("/me/student-verification", middleware.DenyImpersonation(), userHandler.RequestStudentVerification)
+			users.POST("/me/student-verification/confirm", middleware.DenyImpersonation(), userHandler.ConfirmStudentVerification)
+			users.DELETE("/me/student-verification", middleware.DenyImpersonation(), userHandler.RemoveStudentVerification)
+			users.POST("/me/deletion", middleware.DenyImpersonation(), userHandler.RequestDeletion)
+			users.DELETE("/me/deletion", middleware.DenyImpersonation(), userHandler.CancelDeletion)
+			users.GET("/me/export", middleware.DenyImpersonation(), userHandler.Export)
+			users.PUT("/me/privacy", middleware.DenyImpersonation(), userHandler.UpdatePrivacy)
+			users.GET("/me/invitations", collaboratorHandler.Invitations)
 
-			// Admin only
-			users.GET("", middleware.RequireAdmin(), userHandler.List)
-			users.DELETE("/:id", middleware.RequireAdmin(), userHandler.Delete)
-			users.POST("/:id/block", middleware.RequireModerator(), userHandler.Block)
-			users.POST("/:id/unblock", middleware.RequireModerator(), userHandler.Unblock)
+			// Admin and moderator actions
+			users.GET("", middleware.RequirePermission(policy.UserList), userHandler.List)
+			users.DELETE("/:id", middleware.RequirePermission(policy.UserDelete), userHandler.Delete)
+			users.POST("/:id/logout", middleware.RequirePermission(policy.UserRevokeSessions), userHandler.ForceLogout)
+			users.DELETE("/:id/lockout", middleware.RequirePermission(policy.UserClearLockout), userHandler.ClearLockout)
+			users.POST("/:id/impersonate", middleware.RequirePermission(policy.UserImpersonate), userHandler.Impersonate)
+			users.POST("/:id/block", middleware.RequirePermission(policy.UserBlock), userHandler.Block)
+			users.POST("/:id/unblock", middleware.RequirePermission(policy.UserBlock), userHandler.Unblock)
+		}
+
+		// Portfolio routes by username and project slug
+		portfolio := api.Group("/u")
+		portfolio.Use(middleware.OptionalAuthMiddleware())
+		{
+			portfolio.GET("/:username", userHandler.Get)
+			portfolio.GET("/:username/:slug", projectHandler.Get)
 		}
 
 		// Project routes
@@ -69,26 +129,57 @@ func SetupV1Routes(r *gin.Engine) {
 			projects.GET("/:id", projectHandler.Get)
 			projects.POST("/:id/view", projectHandler.View)
 			projects.GET("/:id/comments", commentHandler.List)
+			projects.GET("/:id/revisions", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), projectRevisionHandler.List)
+			projects.GET("/:id/revisions/:rev", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), projectRevisionHandler.Get)
+			projects.GET("/:id/revisions/:rev/diff", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), projectRevisionHandler.Diff)
+			projects.GET("/:id/collaborators", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), collaboratorHandler.List)
+			projects.GET("/:id/transfers", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), transferHandler.ProjectHistory)
+			projects.GET("/:id/releases", releaseHandler.List)
+			projects.GET("/:id/releases/:rid/download", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), releaseHandler.Download)
 
 			// Protected project routes
 			protectedProjects := projects.Group("")
-			protectedProjects.Use(middleware.AuthMiddleware())
+			protectedProjects.Use(middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeProjectsWrite))
 			{
-				protectedProjects.POST("", projectHandler.Create)
+				protectedProjects.POST("", middleware.RequireVerifiedEmail(), projectHandler.Create)
 				protectedProjects.PUT("/:id", projectHandler.Update)
 				protectedProjects.DELETE("/:id", projectHandler.Delete)
-				protectedProjects.POST("/:id/like", projectHandler.Like)
-				protectedProjects.POST("/:id/comments", commentHandler.Create)
+				protectedProjects.POST("/:id/revisions/:rev/restore", projectRevisionHandler.Restore)
+				protectedProjects.POST("/:id/collaborators", collaboratorHandler.Invite)
+				protectedProjects.POST("/:id/collaborators/accept", collaboratorHandler.Accept)
+				protectedProjects.PUT("/:id/collaborators/:userId", collaboratorHandler.UpdateRole)
+				protectedProjects.DELETE("/:id/collaborators/:userId", collaboratorHandler.Remove)
+				protectedProjects.PUT("/:id/revenue-split", middleware.RequireSessionAuth(), middleware.DenyImpersonation(), collaboratorHandler.UpdateRevenueSplit)
+				protectedProjects.POST("/:id/transfer", middleware.RequireSessionAuth(), middleware.DenyImpersonation(), transferHandler.RequestProject)
+				protectedProjects.POST("/:id/releases", releaseHandler.Create)
+				protectedProjects.DELETE("/:id/releases/:rid", releaseHandler.Delete)
+				protectedProjects.POST("/:id/like", middleware.RequireSessionAuth(), projectHandler.Like)
+				protectedProjects.POST("/:id/comments", middleware.RequireSessionAuth(), middleware.RequireVerifiedEmail(), commentHandler.Create)
 
-				// Moderator only
-				protectedProjects.POST("/:id/block", middleware.RequireModerator(), projectHandler.Block)
-				protectedProjects.POST("/:id/unblock", middleware.RequireModerator(), projectHandler.Unblock)
+				// Moderation
+				protectedProjects.POST("/:id/block", middleware.RequirePermission(policy.ProjectBlock), projectHandler.Block)
+				protectedProjects.POST("/:id/unblock", middleware.RequirePermission(policy.ProjectBlock), projectHandler.Unblock)
+				protectedProjects.POST("/:id/approve", middleware.RequirePermission(policy.ProjectReview), projectHandler.Approve)
+				protectedProjects.POST("/:id/reject", middleware.RequirePermission(policy.ProjectReview), projectHandler.Reject)
 			}
 		}
 
+		// Signed release downloads (the token authorizes the request)
+		api.GET("/downloads/:token", releaseHandler.Serve)
+
+		// Ownership transfer routes
+		transfers := api.Group("/transfers")
+		transfers.Use(middleware.AuthMiddleware(), middleware.RequireSessionAuth())
+		{
+			transfers.GET("", transferHandler.List)
+			transfers.POST("/:id/accept", middleware.DenyImpersonation(), transferHandler.Accept)
+			transfers.POST("/:id/decline", middleware.DenyImpersonation(), transferHandler.Decline)
+			transfers.POST("/:id/cancel", middleware.DenyImpersonation(), transferHandler.Cancel)
+		}
+
 		// Comment routes (for deletion)
 		comments := api.Group("/comments")
-		comments.Use(middleware.AuthMiddleware())
+		comments.Use(middleware.AuthMiddleware(), middleware.RequireSessionAuth())
 		{
 			comments.DELETE("/:id", commentHandler.Delete)
 		}
@@ -100,14 +191,16 @@ func SetupV1Routes(r *gin.Engine) {
 			articles.GET("", articleHandler.List)
 			articles.GET("/:id", articleHandler.Get)
 			articles.POST("/:id/view", articleHandler.View)
+			articles.GET("/:id/transfers", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead), transferHandler.ArticleHistory)
 
 			// Protected article routes
 			protectedArticles := articles.Group("")
-			protectedArticles.Use(middleware.AuthMiddleware())
+			protectedArticles.Use(middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeArticlesWrite))
 			{
 				protectedArticles.POST("", articleHandler.Create)
 				protectedArticles.PUT("/:id", articleHandler.Update)
 				protectedArticles.DELETE("/:id", articleHandler.Delete)
+				protectedArticles.POST("/:id/transfer", middleware.RequireSessionAuth(), middleware.DenyImpersonation(), transferHandler.RequestArticle)
 			}
 		}
 
@@ -119,14 +212,17 @@ func SetupV1Routes(r *gin.Engine) {
 
 			// Protected transaction routes
 			transactions.Use(middleware.AuthMiddleware())
-			transactions.POST("", transactionHandler.Create)
-			transactions.GET("", transactionHandler.List)
-			transactions.GET("/check/:projectId", transactionHandler.CheckPurchase)
+			transactions.POST("", middleware.RequireSessionAuth(), middleware.DenyImpersonation(), middleware.RequireVerifiedEmail(), transactionHandler.Create)
+			transactions.GET("", middleware.RequireScope(models.ScopeRead), transactionHandler.List)
+			transactions.GET("/check/:projectId", middleware.RequireScope(models.ScopeRead), transactionHandler.CheckPurchase)
 
 			// Admin only
-			transactions.GET("/admin", middleware.RequireAdmin(), transactionHandler.AdminList)
+			transactions.GET("/admin", middleware.RequirePermission(policy.TransactionListAny), transactionHandler.AdminList)
 		}
 
+		// Full-text search over projects and articles
+		api.GET("/search", searchHandler.Search)
+
 		// Category routes
 		categories := api.Group("/categories")
 		{
@@ -134,15 +230,53 @@ func SetupV1Routes(r *gin.Engine) {
 			categories.GET("/:id", categoryHandler.Get)
 
 			// Admin only
-			categories.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+			categories.Use(middleware.AuthMiddleware(), middleware.RequirePermission(policy.CategoryManage))
 			categories.POST("", categoryHandler.Create)
 			categories.PUT("/:id", categoryHandler.Update)
 			categories.DELETE("/:id", categoryHandler.Delete)
 		}
 
+		// Technology routes
+		technologies := api.Group("/technologies")
+		{
+			technologies.GET("", technologyHandler.List)
+
+			// Admin only
+			technologies.Use(middleware.AuthMiddleware(), middleware.RequirePermission(policy.TechnologyManage))
+			technologies.POST("", technologyHandler.Create)
+			technologies.PUT("/:id", technologyHandler.Update)
+			technologies.POST("/:id/merge", technologyHandler.Merge)
+		}
+
+		// University routes
+		universities := api.Group("/universities")
+		{
+			universities.GET("", universityHandler.List)
+
+			// Admin only
+			universities.Use(middleware.AuthMiddleware(), middleware.RequirePermission(policy.UniversityManage))
+			universities.POST("", universityHandler.Create)
+			universities.PUT("/:id", universityHandler.Update)
+			universities.DELETE("/:id", universityHandler.Delete)
+		}
+
+		// Role routes
+		roles := api.Group("/roles")
+		roles.Use(middleware.AuthMiddleware(), middleware.RequirePermission(policy.RoleView))
+		{
+			roles.GET("/permissions", roleHandler.Permissions)
+		}
+
+		// Audit log routes
+		auditLogs := api.Group("/audit-logs")
+		auditLogs.Use(middleware.AuthMiddleware(), middleware.RequirePermission(policy.AuditLogView))
+		{
+			auditLogs.GET("", auditLogHandler.List)
+		}
+
 		// Upload routes
 		upload := api.Group("/upload")
-		upload.Use(middleware.AuthMiddleware())
+		upload.Use(middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeProjectsWrite, models.ScopeArticlesWrite))
 		{
 			upload.POST("", uploadHandler.Upload)
 			upload.DELETE("/:filename", uploadHandler.Delete)
@@ -153,7 +287,7 @@ func SetupV1Routes(r *gin.Engine) {
 		{
 			gamification.GET("/config", gamificationHandler.GetConfig)
 
-			gamification.Use(middleware.AuthMiddleware())
+			gamification.Use(middleware.AuthMiddleware(), middleware.RequireScope(models.ScopeRead))
 			gamification.GET("/stats", gamificationHandler.GetStats)
 		}
 	}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/mailer"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountDeletionInput confirms a deletion request. Accounts with a password must enter it again.
+type AccountDeletionInput struct {
+	Password string `json:"password"`
+}
+
+// RequestAccountDeletion schedules the account to be anonymized after the grace period.
+// The user can keep logging in and cancel until then.
+func RequestAccountDeletion(userID uuid.UUID, input *AccountDeletionInput) (time.Time, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if user.DeletionScheduledAt != nil {
+		return time.Time{}, errors.New("penghapusan akun sudah dijadwalkan")
+	}
+	// Staff accounts are removed by another admin so the platform is never left without one
+	if user.Role != models.RoleUser {
+		return time.Time{}, errors.New("akun staf tidak dapat dihapus sendiri, hubungi admin")
+	}
+	if user.PasswordHash != nil && !utils.CheckPassword(*user.PasswordHash, input.Password) {
+		return time.Time{}, errors.New("password salah")
+	}
+
+	cfg := config.GetConfig()
+	scheduledAt := time.Now().AddDate(0, 0, cfg.Auth.AccountDeletionGraceDays)
+
+	db := database.GetDB()
+	if err := db.Model(user).Update("deletion_scheduled_at", scheduledAt).Error; err != nil {
+		return time.Time{}, fmt.Errorf("gagal menjadwalkan penghapusan akun: %w", err)
+	}
+
+	// Tell the owner, in case someone else requested the deletion from a stolen session
+	go func(user models.User) {
+		body := fmt.Sprintf(
+			"Halo %s,\n\nAkun Campus Project Hub Anda dijadwalkan untuk dihapus pada %s.\n"+
+				"Sebelum tanggal tersebut Anda masih dapat login dan membatalkan penghapusan dari halaman pengaturan akun.\n\n"+
+				"Setelah dihapus, profil, project dan artikel Anda tidak dapat dipulihkan.",
+			user.Name, scheduledAt.Format("02 January 2006 15:04 MST"),
+		)
+		err := mailer.GetMailer().Send(mailer.Message{
+			To:      user.Email,
+			Subject: "Penghapusan akun Campus Project Hub",
+			Body:    body,
+		})
+		if err != nil {
+			log.Printf("Failed to send account deletion email to %s: %v", user.Email, err)
+		}
+	}(*user)
+
+	return scheduledAt, nil
+}
+
+// CancelAccountDeletion keeps the account when a deletion is still pending
+func CancelAccountDeletion(userID uuid.UUID) error {
+	db := database.GetDB()
+	result := db.Model(&models.User{}).
+		Where("id = ? AND deletion_scheduled_at IS NOT NULL AND status <> ?", userID, models.StatusDeleted).
+		Update("deletion_scheduled_at", nil)
+	if result.Error != nil {
+		return fmt.Errorf("gagal membatalkan penghapusan akun: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("tidak ada penghapusan akun yang dijadwalkan")
+	}
+	return nil
+}
+
+// PurgeDueAccounts anonymizes every account whose grace period has passed
+func PurgeDueAccounts() (int, error) {
+	db := database.GetDB()
+
+	var userIDs []uuid.UUID
+	err := db.Model(&models.User{}).
+		Where("deletion_scheduled_at <= ? AND status <> ?", time.Now(), models.StatusDeleted).
+		Pluck("id", &userIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		if err := AnonymizeUser(userID); err != nil {
+			log.Printf("Failed to anonymize user %s: %v", userID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// AnonymizeUser removes the user's personal data while keeping the row, so comments,
+// transactions and moderation records that reference it stay intact. Projects without
+// sales and articles are deleted; sold projects are hidden because buyers keep their purchase.
+func AnonymizeUser(userID uuid.UUID) error {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsDeleted() {
+		return nil
+	}
+
+	db := database.GetDB()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Credentials and login state
+		for _, model := range []interface{}{
+			&models.Session{},
+			&models.UserIdentity{},
+			&models.PersonalAccessToken{},
+			&models.RecoveryCode{},
+			&models.AuthCode{},
+			&models.PasswordResetToken{},
+			&models.StudentVerification{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Likes, keeping the counters on the liked projects in step
+		if err := tx.Exec(
+			"UPDATE projects SET likes = likes - 1 WHERE likes > 0 AND id IN (SELECT project_id FROM project_likes WHERE user_id = ?)",
+			userID,
+		).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.ProjectLike{}).Error; err != nil {
+			return err
+		}
+
+		// Content
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Article{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND NOT EXISTS (SELECT 1 FROM transactions WHERE transactions.project_id = projects.id)", userID).
+			Delete(&models.Project{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Project{}).Where("user_id = ?", userID).
+			Update("status", models.ProjectStatusDraft).Error; err != nil {
+			return err
+		}
+
+		// Memberships in other people's teams, handing any revenue share back to the project's author
+		if err := tx.Exec(
+			`UPDATE project_collaborators AS author SET revenue_share = author.revenue_share + member.revenue_share
+			FROM project_collaborators AS member JOIN projects ON projects.id = member.project_id
+			WHERE member.user_id = ? AND projects.user_id <> ?
+			AND author.project_id = member.project_id AND author.user_id = projects.user_id`,
+			userID, userID,
+		).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND project_id NOT IN (?)", userID, tx.Model(&models.Project{}).Select("id").Where("user_id = ?", userID)).
+			Delete(&models.ProjectCollaborator{}).Error; err != nil {
+			return err
+		}
+
+		// Open ownership transfers to or from the account
+		if err := tx.Model(&models.OwnershipTransfer{}).
+			Where("status = ? AND (from_user_id = ? OR to_user_id = ?)", models.TransferPending, userID, userID).
+			Updates(map[string]interface{}{"status": models.TransferCancelled, "responded_at": time.Now()}).Error; err != nil {
+			return err
+		}
+
+		// Old usernames and project slugs must not lead back to the account
+		if err := tx.Where("resource_id = ? OR scope_id = ?", userID, userID).Delete(&models.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		// The profile itself. Comments now show the anonymous name.
+		now := time.Now()
+		return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"email":                  fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+			"username":               "deleted-" + strings.ReplaceAll(userID.String(), "-", "")[:12],
+			"password_hash":          nil,
+			"name":                   models.DeletedUserName,
+			"avatar_url":             nil,
+			"university":             nil,
+			"major":                  nil,
+			"bio":                    nil,
+			"phone":                  nil,
+			"show_email":             false,
+			"show_phone":             false,
+			"role":                   models.RoleUser,
+			"status":                 models.StatusDeleted,
+			"total_exp":              0,
+			"email_verified_at":      nil,
+			"totp_secret":            nil,
+			"totp_enabled_at":        nil,
+			"totp_last_step":         0,
+			"campus_email":           nil,
+			"verified_university_id": nil,
+			"student_verified_at":    nil,
+			"deletion_scheduled_at":  nil,
+			"anonymized_at":          now,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("gagal menghapus akun: %w", err)
+	}
+
+	if err := ClearLoginLockout(user.Email, ""); err != nil {
+		log.Printf("Failed to clear login attempts of deleted user %s: %v", userID, err)
+	}
+	return nil
+}
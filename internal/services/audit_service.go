@@ -0,0 +1,51 @@
+package services
+
+import (
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// AuditLogFilter narrows down the audit log list
+type AuditLogFilter struct {
+	Action   string
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+}
+
+// newAuditLog describes an action taken by actorID from the given client
+func newAuditLog(actorID uuid.UUID, action, targetType string, targetID *uuid.UUID, client ClientInfo) *models.AuditLog {
+	return &models.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IPAddress:  optionalString(client.IPAddress),
+		UserAgent:  optionalString(client.UserAgent),
+	}
+}
+
+// ListAuditLogs returns a page of audit log entries, newest first
+func ListAuditLogs(filter AuditLogFilter, page, perPage int) ([]models.AuditLog, int64, error) {
+	db := database.GetDB()
+	query := db.Model(&models.AuditLog{})
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AuditLog
+	err := query.Order("created_at DESC").Offset((page - 1) * perPage).Limit(perPage).Find(&logs).Error
+	return logs, total, err
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// AuthCodeTTL is how long the frontend has to exchange an OAuth login code
+const AuthCodeTTL = time.Minute
+
+// CreateAuthCode issues a single-use code the frontend can exchange for tokens
+func CreateAuthCode(userID uuid.UUID) (string, error) {
+	code, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	authCode := models.AuthCode{
+		CodeHash:  utils.HashToken(code),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(AuthCodeTTL),
+	}
+
+	db := database.GetDB()
+	if err := db.Create(&authCode).Error; err != nil {
+		return "", fmt.Errorf("gagal membuat kode otorisasi: %w", err)
+	}
+
+	// Opportunistically clean up codes that can no longer be used
+	db.Where("expires_at < ?", time.Now().Add(-time.Hour)).Delete(&models.AuthCode{})
+
+	return code, nil
+}
+
+// ExchangeAuthCode consumes a code and starts a session for its user
+func ExchangeAuthCode(code string, client ClientInfo) (*AuthResult, error) {
+	db := database.GetDB()
+	codeHash := utils.HashToken(code)
+
+	// Mark the code as used atomically so it can only be exchanged once
+	result := db.Model(&models.AuthCode{}).
+		Where("code_hash = ? AND used_at IS NULL AND expires_at > ?", codeHash, time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, fmt.Errorf("gagal memproses kode otorisasi: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("kode otorisasi tidak valid atau sudah kedaluwarsa")
+	}
+
+	var authCode models.AuthCode
+	if err := db.Preload("User").First(&authCode, "code_hash = ?", codeHash).Error; err != nil {
+		return nil, errors.New("kode otorisasi tidak valid atau sudah kedaluwarsa")
+	}
+
+	user := authCode.User
+	if user.Status == models.StatusBlocked {
+		return nil, errors.New("akun Anda telah diblokir")
+	}
+
+	return completeLogin(&user, client)
+}
@@ -3,11 +3,13 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/models"
 	"github.com/campus-project-hub/api/internal/utils"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // RegisterInput for user registration
@@ -24,7 +26,7 @@ type LoginInput struct {
 }
 
 // Register creates a new user account
-func Register(input *RegisterInput) (*AuthResult, error) {
+func Register(input *RegisterInput, client ClientInfo) (*AuthResult, error) {
 	db := database.GetDB()
 
 	// Check if email exists
@@ -52,8 +54,15 @@ func Register(input *RegisterInput) (*AuthResult, error) {
 		return nil, fmt.Errorf("gagal membuat akun: %w", err)
 	}
 
+	// Send verification email without blocking registration
+	go func(user models.User) {
+		if err := SendVerificationEmail(&user); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+		}
+	}(user)
+
 	// Generate tokens
-	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, string(user.Role))
+	tokenPair, err := CreateSession(&user, client)
 	if err != nil {
 		return nil, fmt.Errorf("gagal membuat token: %w", err)
 	}
@@ -65,12 +74,18 @@ func Register(input *RegisterInput) (*AuthResult, error) {
 }
 
 // Login authenticates a user
-func Login(input *LoginInput) (*AuthResult, error) {
+func Login(input *LoginInput, client ClientInfo) (*AuthResult, error) {
 	db := database.GetDB()
 
-	// Find user by email
+	// Refuse to check the password while the account or IP address is locked out
+	if err := checkLoginAllowed(input.Email, client); err != nil {
+		return nil, err
+	}
+
+	// Find user by email. Unknown emails count as failures too, so lockouts do not reveal which accounts exist.
 	var user models.User
 	if err := db.Where("email = ?", input.Email).First(&user).Error; err != nil {
+		recordLoginFailure(input.Email, client)
 		return nil, errors.New("email atau password salah")
 	}
 
@@ -86,46 +101,40 @@ func Login(input *LoginInput) (*AuthResult, error) {
 
 	// Verify password
 	if !utils.CheckPassword(*user.PasswordHash, input.Password) {
+		recordLoginFailure(input.Email, client)
 		return nil, errors.New("email atau password salah")
 	}
 
-	// Generate tokens
-	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, string(user.Role))
-	if err != nil {
-		return nil, fmt.Errorf("gagal membuat token: %w", err)
+	// A 2FA challenge keeps counting against the account until the code is verified
+	if !user.IsTwoFactorEnabled() {
+		recordLoginSuccess(input.Email)
 	}
 
-	return &AuthResult{
-		User:      &user,
-		TokenPair: tokenPair,
-	}, nil
+	return completeLogin(&user, client)
 }
 
-// RefreshTokens generates new token pair from refresh token
-func RefreshTokens(refreshToken string) (*utils.TokenPair, error) {
-	claims, err := utils.ValidateRefreshToken(refreshToken)
-	if err != nil {
-		return nil, errors.New("refresh token tidak valid")
+// completeLogin starts a session, or a 2FA challenge when the user has 2FA enabled
+func completeLogin(user *models.User, client ClientInfo) (*AuthResult, error) {
+	if user.IsTwoFactorEnabled() {
+		return startTwoFactorChallenge(user)
 	}
 
-	db := database.GetDB()
-	var user models.User
-	if err := db.First(&user, "id = ?", claims.UserID).Error; err != nil {
-		return nil, errors.New("user tidak ditemukan")
-	}
-
-	if user.Status == models.StatusBlocked {
-		return nil, errors.New("akun Anda telah diblokir")
+	tokenPair, err := CreateSession(user, client)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat token: %w", err)
 	}
 
-	return utils.GenerateTokenPair(user.ID, user.Email, string(user.Role))
+	return &AuthResult{
+		User:      user,
+		TokenPair: tokenPair,
+	}, nil
 }
 
 // GetUserByID retrieves a user by ID
 func GetUserByID(id uuid.UUID) (*models.User, error) {
 	db := database.GetDB()
 	var user models.User
-	if err := db.First(&user, "id = ?", id).Error; err != nil {
+	if err := db.Preload("VerifiedUniversity").First(&user, "id = ?", id).Error; err != nil {
 		return nil, errors.New("user tidak ditemukan")
 	}
 	return &user, nil
@@ -133,6 +142,7 @@ func GetUserByID(id uuid.UUID) (*models.User, error) {
 
 // UpdateUserInput for profile updates
 type UpdateUserInput struct {
+	Username   *string `json:"username" validate:"omitempty,min=3,max=30"`
 	Name       *string `json:"name" validate:"omitempty,min=2,max=100"`
 	University *string `json:"university" validate:"omitempty,max=255"`
 	Major      *string `json:"major" validate:"omitempty,max=255"`
@@ -169,13 +179,58 @@ func UpdateUser(userID uuid.UUID, input *UpdateUserInput) (*models.User, error)
 		user.AvatarURL = input.AvatarURL
 	}
 
-	if err := db.Save(&user).Error; err != nil {
-		return nil, fmt.Errorf("gagal memperbarui profil: %w", err)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if input.Username != nil {
+			if err := changeUsername(tx, &user, *input.Username); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&user).Error; err != nil {
+			return fmt.Errorf("gagal memperbarui profil: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &user, nil
 }
 
+// UpdatePrivacyInput changes which profile fields other users can see
+type UpdatePrivacyInput struct {
+	ShowEmail           *bool `json:"showEmail"`
+	ShowPhone           *bool `json:"showPhone"`
+	ShowUniversity      *bool `json:"showUniversity"`
+	HideFromLeaderboard *bool `json:"hideFromLeaderboard"`
+}
+
+// UpdatePrivacySettings updates the given privacy settings and leaves the others unchanged
+func UpdatePrivacySettings(userID uuid.UUID, input *UpdatePrivacyInput) (*models.User, error) {
+	updates := map[string]interface{}{}
+	if input.ShowEmail != nil {
+		updates["show_email"] = *input.ShowEmail
+	}
+	if input.ShowPhone != nil {
+		updates["show_phone"] = *input.ShowPhone
+	}
+	if input.ShowUniversity != nil {
+		updates["show_university"] = *input.ShowUniversity
+	}
+	if input.HideFromLeaderboard != nil {
+		updates["hide_from_leaderboard"] = *input.HideFromLeaderboard
+	}
+
+	if len(updates) > 0 {
+		db := database.GetDB()
+		if err := db.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+			return nil, fmt.Errorf("gagal memperbarui pengaturan privasi: %w", err)
+		}
+	}
+
+	return GetUserByID(userID)
+}
+
 // AddUserExp adds EXP to user
 func AddUserExp(userID uuid.UUID, exp int) error {
 	db := database.GetDB()
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/mailer"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WithCollaborators preloads the accepted team members of projects with their profiles.
+// Use it as a scope wherever a project is shown or checked with policy.Can.
+func WithCollaborators(db *gorm.DB) *gorm.DB {
+	return db.Preload("Collaborators", func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", models.CollaboratorAccepted).Order("created_at ASC")
+	}).Preload("Collaborators.User")
+}
+
+// AddProjectOwner makes the author of a new project its owner with the full revenue share
+func AddProjectOwner(tx *gorm.DB, project *models.Project) error {
+	now := time.Now()
+	owner := models.ProjectCollaborator{
+		ProjectID:    project.ID,
+		UserID:       project.UserID,
+		Role:         models.CollaboratorOwner,
+		Status:       models.CollaboratorAccepted,
+		RevenueShare: 100,
+		AcceptedAt:   &now,
+	}
+	if err := tx.Create(&owner).Error; err != nil {
+		return err
+	}
+	project.Collaborators = []models.ProjectCollaborator{owner}
+	return nil
+}
+
+// InviteCollaboratorInput invites a user to a project's team by username
+type InviteCollaboratorInput struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required,oneof=owner maintainer contributor"`
+}
+
+// InviteCollaborator creates a pending membership that the invitee has to accept
+func InviteCollaborator(project *models.Project, inviterID uuid.UUID, input *InviteCollaboratorInput) (*models.ProjectCollaborator, error) {
+	db := database.GetDB()
+
+	var invitee models.User
+	err := db.Where("username = ? AND status = ?", strings.ToLower(strings.TrimSpace(input.Username)), models.StatusActive).
+		First(&invitee).Error
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	var count int64
+	db.Model(&models.ProjectCollaborator{}).Where("project_id = ? AND user_id = ?", project.ID, invitee.ID).Count(&count)
+	if count > 0 {
+		return nil, errors.New("user sudah menjadi anggota atau sudah diundang")
+	}
+
+	collaborator := models.ProjectCollaborator{
+		ProjectID: project.ID,
+		UserID:    invitee.ID,
+		Role:      models.CollaboratorRole(input.Role),
+		Status:    models.CollaboratorPending,
+		InvitedBy: &inviterID,
+	}
+	if err := db.Create(&collaborator).Error; err != nil {
+		return nil, fmt.Errorf("gagal mengundang kolaborator: %w", err)
+	}
+	collaborator.User = invitee
+
+	go func(invitee models.User, title string) {
+		link := config.GetConfig().FrontendBaseURL() + "/invitations"
+		body := fmt.Sprintf(
+			"Halo %s,\n\nAnda diundang untuk bergabung sebagai %s di project \"%s\" di Campus Project Hub.\n"+
+				"Buka tautan berikut untuk menerima atau menolak undangan:\n\n%s",
+			invitee.Name, input.Role, title, link,
+		)
+		if err := mailer.GetMailer().Send(mailer.Message{
+			To:      invitee.Email,
+			Subject: "Undangan kolaborasi project Campus Project Hub",
+			Body:    body,
+		}); err != nil {
+			log.Printf("Failed to send collaborator invitation to %s: %v", invitee.Email, err)
+		}
+	}(invitee, project.Title)
+
+	return &collaborator, nil
+}
+
+// ListCollaborators returns the whole team of a project including pending invitations
+func ListCollaborators(projectID uuid.UUID) ([]models.ProjectCollaborator, error) {
+	var collaborators []models.ProjectCollaborator
+	err := database.GetDB().Preload("User").
+		Where("project_id = ?", projectID).
+		Order("created_at ASC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+// ListInvitations returns the user's pending invitations
+func ListInvitations(userID uuid.UUID) ([]models.ProjectCollaborator, error) {
+	var invitations []models.ProjectCollaborator
+	err := database.GetDB().Preload("Project").
+		Where("user_id = ? AND status = ?", userID, models.CollaboratorPending).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// AcceptInvitation makes the invitee a member of the project. New owners start without a
+// revenue share until the split is changed.
+func AcceptInvitation(projectID, userID uuid.UUID) error {
+	now := time.Now()
+	result := database.GetDB().Model(&models.ProjectCollaborator{}).
+		Where("project_id = ? AND user_id = ? AND status = ?", projectID, userID, models.CollaboratorPending).
+		Updates(map[string]interface{}{
+			"status":      models.CollaboratorAccepted,
+			"accepted_at": now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("gagal menerima undangan: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("undangan tidak ditemukan")
+	}
+	return nil
+}
+
+// UpdateCollaboratorRole changes a member's role. The project's author always stays an owner.
+func UpdateCollaboratorRole(project *models.Project, userID uuid.UUID, role models.CollaboratorRole) error {
+	if userID == project.UserID {
+		return errors.New("peran pemilik utama project tidak dapat diubah")
+	}
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		collaborator, err := findCollaborator(tx, project.ID, userID)
+		if err != nil {
+			return err
+		}
+		if role != models.CollaboratorOwner {
+			if err := returnRevenueShare(tx, project, collaborator); err != nil {
+				return err
+			}
+		}
+		return tx.Model(collaborator).Update("role", role).Error
+	})
+}
+
+// RemoveCollaborator takes a member off the team, or withdraws or declines an invitation.
+// Their revenue share goes back to the project's author.
+func RemoveCollaborator(project *models.Project, userID uuid.UUID) error {
+	if userID == project.UserID {
+		return errors.New("pemilik utama project tidak dapat dikeluarkan")
+	}
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		collaborator, err := findCollaborator(tx, project.ID, userID)
+		if err != nil {
+			return err
+		}
+		if err := returnRevenueShare(tx, project, collaborator); err != nil {
+			return err
+		}
+		return tx.Delete(collaborator).Error
+	})
+}
+
+func findCollaborator(tx *gorm.DB, projectID, userID uuid.UUID) (*models.ProjectCollaborator, error) {
+	var collaborator models.ProjectCollaborator
+	if err := tx.Where("project_id = ? AND user_id = ?", projectID, userID).First(&collaborator).Error; err != nil {
+		return nil, errors.New("kolaborator tidak ditemukan")
+	}
+	return &collaborator, nil
+}
+
+// returnRevenueShare moves a member's share to the project's author so the owners' shares keep adding up to 100
+func returnRevenueShare(tx *gorm.DB, project *models.Project, collaborator *models.ProjectCollaborator) error {
+	if collaborator.RevenueShare == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.ProjectCollaborator{}).
+		Where("project_id = ? AND user_id = ?", project.ID, project.UserID).
+		Update("revenue_share", gorm.Expr("revenue_share + ?", collaborator.RevenueShare)).Error; err != nil {
+		return err
+	}
+	return tx.Model(collaborator).Update("revenue_share", 0).Error
+}
+
+// RevenueShareInput is one owner's percentage of each sale
+type RevenueShareInput struct {
+	UserID  string `json:"userId" validate:"required,uuid"`
+	Percent int    `json:"percent" validate:"min=0,max=100"`
+}
+
+// UpdateRevenueSplitInput sets the percentages of every owner of a project
+type UpdateRevenueSplitInput struct {
+	Shares []RevenueShareInput `json:"shares" validate:"required,min=1,dive"`
+}
+
+// UpdateRevenueSplit replaces the owners' revenue shares. Every accepted owner must be
+// listed exactly once and the percentages must add up to 100.
+func UpdateRevenueSplit(project *models.Project, input *UpdateRevenueSplitInput) ([]models.ProjectCollaborator, error) {
+	db := database.GetDB()
+
+	var owners []models.ProjectCollaborator
+	if err := db.Where("project_id = ? AND role = ? AND status = ?", project.ID, models.CollaboratorOwner, models.CollaboratorAccepted).
+		Find(&owners).Error; err != nil {
+		return nil, err
+	}
+
+	shares, err := checkRevenueSplit(owners, input.Shares)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i := range owners {
+			owners[i].RevenueShare = shares[owners[i].UserID]
+			if err := tx.Model(&owners[i]).Update("revenue_share", owners[i].RevenueShare).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan pembagian pendapatan: %w", err)
+	}
+
+	return ListCollaborators(project.ID)
+}
+
+// checkRevenueSplit maps each owner to their new percentage, rejecting duplicates, owners
+// left out, users who are not owners and totals other than 100
+func checkRevenueSplit(owners []models.ProjectCollaborator, input []RevenueShareInput) (map[uuid.UUID]int, error) {
+	shares := make(map[uuid.UUID]int, len(input))
+	total := 0
+	for _, share := range input {
+		userID, _ := uuid.Parse(share.UserID)
+		if _, duplicate := shares[userID]; duplicate {
+			return nil, errors.New("setiap pemilik hanya boleh disebut sekali")
+		}
+		shares[userID] = share.Percent
+		total += share.Percent
+	}
+	if total != 100 {
+		return nil, errors.New("total pembagian pendapatan harus 100%")
+	}
+	if len(shares) != len(owners) {
+		return nil, errors.New("pembagian pendapatan harus mencakup semua pemilik project")
+	}
+	for _, owner := range owners {
+		if _, ok := shares[owner.UserID]; !ok {
+			return nil, errors.New("pembagian pendapatan harus mencakup semua pemilik project")
+		}
+	}
+	return shares, nil
+}
+
+// splitSale divides a sale among the project's owners by their revenue share. Rounding
+// leftovers go to the project's author so the shares add up to the amount.
+func splitSale(tx *gorm.DB, project *models.Project, transaction *models.Transaction) ([]models.TransactionShare, error) {
+	var owners []models.ProjectCollaborator
+	if err := tx.Where("project_id = ? AND role = ? AND status = ? AND revenue_share > 0",
+		project.ID, models.CollaboratorOwner, models.CollaboratorAccepted).
+		Find(&owners).Error; err != nil {
+		return nil, err
+	}
+	return divideSale(project, owners, transaction), nil
+}
+
+// divideSale computes the shares of a sale for the given owners. Whatever the percentages
+// leave over, from rounding or from shares adding up to less than 100, goes to the author.
+func divideSale(project *models.Project, owners []models.ProjectCollaborator, transaction *models.Transaction) []models.TransactionShare {
+	if len(owners) == 0 {
+		owners = []models.ProjectCollaborator{{UserID: project.UserID, RevenueShare: 100}}
+	}
+
+	shares := make([]models.TransactionShare, len(owners))
+	remainder := transaction.Amount
+	authorIndex := -1
+	for i, owner := range owners {
+		amount := transaction.Amount * owner.RevenueShare / 100
+		shares[i] = models.TransactionShare{
+			TransactionID: transaction.ID,
+			UserID:        owner.UserID,
+			Percent:       owner.RevenueShare,
+			Amount:        amount,
+		}
+		remainder -= amount
+		if owner.UserID == project.UserID {
+			authorIndex = i
+		}
+	}
+
+	if remainder == 0 {
+		return shares
+	}
+	// An author who gave away their whole share still collects the leftover
+	if authorIndex < 0 {
+		return append(shares, models.TransactionShare{
+			TransactionID: transaction.ID,
+			UserID:        project.UserID,
+			Amount:        remainder,
+		})
+	}
+	shares[authorIndex].Amount += remainder
+	return shares
+}
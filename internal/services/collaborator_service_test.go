@@ -0,0 +1,255 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newOwner(userID uuid.UUID, share int) models.ProjectCollaborator {
+	return models.ProjectCollaborator{UserID: userID, Role: models.CollaboratorOwner, Status: models.CollaboratorAccepted, RevenueShare: share}
+}
+
+func TestDivideSale(t *testing.T) {
+	author, coOwner, thirdOwner := uuid.New(), uuid.New(), uuid.New()
+	project := &models.Project{ID: uuid.New(), UserID: author}
+
+	tests := []struct {
+		name   string
+		amount int
+		owners []models.ProjectCollaborator
+		want   map[uuid.UUID]int
+	}{
+		{
+			name:   "author alone",
+			amount: 50000,
+			owners: []models.ProjectCollaborator{newOwner(author, 100)},
+			want:   map[uuid.UUID]int{author: 50000},
+		},
+		{
+			name:   "no owner rows",
+			amount: 50000,
+			want:   map[uuid.UUID]int{author: 50000},
+		},
+		{
+			name:   "even split",
+			amount: 50000,
+			owners: []models.ProjectCollaborator{newOwner(author, 60), newOwner(coOwner, 40)},
+			want:   map[uuid.UUID]int{author: 30000, coOwner: 20000},
+		},
+		{
+			name:   "rounding leftover goes to the author",
+			amount: 10001,
+			owners: []models.ProjectCollaborator{newOwner(coOwner, 33), newOwner(author, 34), newOwner(thirdOwner, 33)},
+			want:   map[uuid.UUID]int{coOwner: 3300, author: 3401, thirdOwner: 3300},
+		},
+		{
+			name:   "shares under 100 leave the rest to the author",
+			amount: 10000,
+			owners: []models.ProjectCollaborator{newOwner(author, 50), newOwner(coOwner, 30)},
+			want:   map[uuid.UUID]int{author: 7000, coOwner: 3000},
+		},
+		{
+			name:   "author without a share still collects the leftover",
+			amount: 10001,
+			owners: []models.ProjectCollaborator{newOwner(coOwner, 50), newOwner(thirdOwner, 50)},
+			want:   map[uuid.UUID]int{coOwner: 5000, thirdOwner: 5000, author: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := &models.Transaction{ID: uuid.New(), Amount: tt.amount}
+			shares := divideSale(project, tt.owners, transaction)
+
+			got := make(map[uuid.UUID]int, len(shares))
+			total := 0
+			for _, share := range shares {
+				if share.TransactionID != transaction.ID {
+					t.Errorf("share of %s belongs to transaction %s", share.UserID, share.TransactionID)
+				}
+				if _, duplicate := got[share.UserID]; duplicate {
+					t.Errorf("%s has more than one share", share.UserID)
+				}
+				got[share.UserID] = share.Amount
+				total += share.Amount
+			}
+
+			if total != tt.amount {
+				t.Errorf("shares add up to %d, want %d", total, tt.amount)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("got %d shares, want %d", len(got), len(tt.want))
+			}
+			for userID, amount := range tt.want {
+				if got[userID] != amount {
+					t.Errorf("share of %s = %d, want %d", userID, got[userID], amount)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRevenueSplit(t *testing.T) {
+	author, coOwner, stranger := uuid.New(), uuid.New(), uuid.New()
+	owners := []models.ProjectCollaborator{newOwner(author, 100), newOwner(coOwner, 0)}
+
+	share := func(userID uuid.UUID, percent int) RevenueShareInput {
+		return RevenueShareInput{UserID: userID.String(), Percent: percent}
+	}
+
+	tests := []struct {
+		name    string
+		input   []RevenueShareInput
+		want    map[uuid.UUID]int
+		wantErr string
+	}{
+		{
+			name:  "every owner once adding up to 100",
+			input: []RevenueShareInput{share(author, 70), share(coOwner, 30)},
+			want:  map[uuid.UUID]int{author: 70, coOwner: 30},
+		},
+		{
+			name:  "owner with no share",
+			input: []RevenueShareInput{share(author, 0), share(coOwner, 100)},
+			want:  map[uuid.UUID]int{author: 0, coOwner: 100},
+		},
+		{
+			name:    "duplicate owner",
+			input:   []RevenueShareInput{share(author, 50), share(author, 50)},
+			wantErr: "hanya boleh disebut sekali",
+		},
+		{
+			name:    "total under 100",
+			input:   []RevenueShareInput{share(author, 60), share(coOwner, 30)},
+			wantErr: "harus 100%",
+		},
+		{
+			name:    "total over 100",
+			input:   []RevenueShareInput{share(author, 70), share(coOwner, 40)},
+			wantErr: "harus 100%",
+		},
+		{
+			name:    "missing owner",
+			input:   []RevenueShareInput{share(author, 100)},
+			wantErr: "semua pemilik",
+		},
+		{
+			name:    "user who is not an owner",
+			input:   []RevenueShareInput{share(author, 70), share(stranger, 30)},
+			wantErr: "semua pemilik",
+		},
+		{
+			name:    "extra user besides every owner",
+			input:   []RevenueShareInput{share(author, 70), share(coOwner, 20), share(stranger, 10)},
+			wantErr: "semua pemilik",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkRevenueSplit(owners, tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("checkRevenueSplit() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkRevenueSplit() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("got %d shares, want %d", len(got), len(tt.want))
+			}
+			for userID, percent := range tt.want {
+				if got[userID] != percent {
+					t.Errorf("share of %s = %d, want %d", userID, got[userID], percent)
+				}
+			}
+		})
+	}
+}
+
+// capturedStatement is an update built by a dry run session
+type capturedStatement struct {
+	sql  string
+	vars []interface{}
+}
+
+// newDryRunDB returns a session that builds updates without a database and records them
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]capturedStatement) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open dry run session: %v", err)
+	}
+
+	var statements []capturedStatement
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, capturedStatement{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}); err != nil {
+		t.Fatalf("register capture callback: %v", err)
+	}
+	return db, &statements
+}
+
+func TestReturnRevenueShare(t *testing.T) {
+	author := uuid.New()
+	project := &models.Project{ID: uuid.New(), UserID: author}
+
+	t.Run("member without a share", func(t *testing.T) {
+		db, statements := newDryRunDB(t)
+		member := newOwner(uuid.New(), 0)
+		member.ID = uuid.New()
+
+		if err := returnRevenueShare(db, project, &member); err != nil {
+			t.Fatalf("returnRevenueShare() error = %v", err)
+		}
+		if len(*statements) != 0 {
+			t.Errorf("ran %d updates, want none", len(*statements))
+		}
+	})
+
+	t.Run("member share moves to the author", func(t *testing.T) {
+		db, statements := newDryRunDB(t)
+		member := newOwner(uuid.New(), 30)
+		member.ID = uuid.New()
+
+		if err := returnRevenueShare(db, project, &member); err != nil {
+			t.Fatalf("returnRevenueShare() error = %v", err)
+		}
+		if len(*statements) != 2 {
+			t.Fatalf("ran %d updates, want 2", len(*statements))
+		}
+
+		credit := (*statements)[0]
+		if !strings.Contains(credit.sql, `"revenue_share"=revenue_share + $1`) || !strings.Contains(credit.sql, "project_id = $2 AND user_id = $3") {
+			t.Errorf("author update = %s", credit.sql)
+		}
+		if len(credit.vars) != 3 || credit.vars[0] != 30 || credit.vars[1] != project.ID || credit.vars[2] != author {
+			t.Errorf("author update vars = %v", credit.vars)
+		}
+
+		reset := (*statements)[1]
+		if !strings.Contains(reset.sql, `"revenue_share"=$1`) || !strings.Contains(reset.sql, `"id" = $2`) {
+			t.Errorf("member update = %s", reset.sql)
+		}
+		if len(reset.vars) != 2 || reset.vars[0] != 0 || reset.vars[1] != member.ID {
+			t.Errorf("member update vars = %v", reset.vars)
+		}
+		if member.RevenueShare != 0 {
+			t.Errorf("member keeps a %d%% share", member.RevenueShare)
+		}
+	})
+}
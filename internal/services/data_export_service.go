@@ -0,0 +1,227 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// UserExport is everything the platform stores about a user, for GET /users/me/export
+type UserExport struct {
+	ExportedAt   time.Time                `json:"exportedAt"`
+	Profile      ExportedProfile          `json:"profile"`
+	Identities   []models.UserIdentity    `json:"identities"`
+	Sessions     []models.SessionResponse `json:"sessions"`
+	Projects     []models.ProjectResponse `json:"projects"`
+	Articles     []models.ArticleResponse `json:"articles"`
+	Comments     []ExportedComment        `json:"comments"`
+	Likes        []ExportedLike           `json:"likes"`
+	Transactions []ExportedTransaction    `json:"transactions"`
+}
+
+// ExportedProfile adds the private profile fields to the regular user response
+type ExportedProfile struct {
+	models.UserResponse
+	CampusEmail       *string    `json:"campusEmail"`
+	EmailVerifiedAt   *time.Time `json:"emailVerifiedAt"`
+	StudentVerifiedAt *time.Time `json:"studentVerifiedAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+type ExportedComment struct {
+	ID           uuid.UUID `json:"id"`
+	ProjectID    uuid.UUID `json:"projectId"`
+	ProjectTitle string    `json:"projectTitle"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ExportedLike struct {
+	ProjectID    uuid.UUID `json:"projectId"`
+	ProjectTitle string    `json:"projectTitle"`
+	LikedAt      time.Time `json:"likedAt"`
+}
+
+// ExportedTransaction is a purchase or a sale. Role is "buyer" or "seller".
+type ExportedTransaction struct {
+	ID              uuid.UUID                `json:"id"`
+	Role            string                   `json:"role"`
+	ProjectID       uuid.UUID                `json:"projectId"`
+	ProjectTitle    string                   `json:"projectTitle"`
+	Amount          int                      `json:"amount"`
+	Status          models.TransactionStatus `json:"status"`
+	MidtransOrderID *string                  `json:"midtransOrderId,omitempty"`
+	CreatedAt       time.Time                `json:"createdAt"`
+}
+
+// BuildUserExport collects the user's data into a single bundle
+func BuildUserExport(userID uuid.UUID) (*UserExport, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.GetDB()
+	export := &UserExport{
+		ExportedAt: time.Now(),
+		Profile: ExportedProfile{
+			UserResponse:      user.ToResponse(),
+			CampusEmail:       user.CampusEmail,
+			EmailVerifiedAt:   user.EmailVerifiedAt,
+			StudentVerifiedAt: user.StudentVerifiedAt,
+			UpdatedAt:         user.UpdatedAt,
+		},
+	}
+
+	if export.Identities, err = ListIdentities(userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := ListActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	export.Sessions = make([]models.SessionResponse, len(sessions))
+	for i := range sessions {
+		export.Sessions[i] = sessions[i].ToResponse(uuid.Nil)
+	}
+
+	var projects []models.Project
+	if err := db.Preload("User").Preload("Images").Where("user_id = ?", userID).Order("created_at ASC").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	commentCounts, err := projectCommentCounts(projects)
+	if err != nil {
+		return nil, err
+	}
+	export.Projects = make([]models.ProjectResponse, len(projects))
+	for i := range projects {
+		export.Projects[i] = projects[i].ToResponse(commentCounts[projects[i].ID])
+	}
+
+	var articles []models.Article
+	if err := db.Preload("User").Where("user_id = ?", userID).Order("created_at ASC").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	export.Articles = make([]models.ArticleResponse, len(articles))
+	for i := range articles {
+		export.Articles[i] = articles[i].ToResponse()
+	}
+
+	export.Comments = []ExportedComment{}
+	if err := db.Table("comments").
+		Select("comments.id, comments.project_id, projects.title AS project_title, comments.content, comments.created_at").
+		Joins("JOIN projects ON projects.id = comments.project_id").
+		Where("comments.user_id = ?", userID).
+		Order("comments.created_at ASC").
+		Scan(&export.Comments).Error; err != nil {
+		return nil, err
+	}
+
+	export.Likes = []ExportedLike{}
+	if err := db.Table("project_likes").
+		Select("project_likes.project_id, projects.title AS project_title, project_likes.created_at AS liked_at").
+		Joins("JOIN projects ON projects.id = project_likes.project_id").
+		Where("project_likes.user_id = ?", userID).
+		Order("project_likes.created_at ASC").
+		Scan(&export.Likes).Error; err != nil {
+		return nil, err
+	}
+
+	var transactions []models.Transaction
+	if err := db.Preload("Project").Where("buyer_id = ? OR seller_id = ?", userID, userID).Order("created_at ASC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	export.Transactions = make([]ExportedTransaction, len(transactions))
+	for i, t := range transactions {
+		role := "buyer"
+		if t.SellerID == userID {
+			role = "seller"
+		}
+		export.Transactions[i] = ExportedTransaction{
+			ID:              t.ID,
+			Role:            role,
+			ProjectID:       t.ProjectID,
+			ProjectTitle:    t.Project.Title,
+			Amount:          t.Amount,
+			Status:          t.Status,
+			MidtransOrderID: t.MidtransOrderID,
+			CreatedAt:       t.CreatedAt,
+		}
+	}
+
+	return export, nil
+}
+
+func projectCommentCounts(projects []models.Project) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(projects))
+	if len(projects) == 0 {
+		return counts, nil
+	}
+
+	ids := make([]uuid.UUID, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+
+	var rows []struct {
+		ProjectID uuid.UUID
+		Count     int
+	}
+	err := database.GetDB().Model(&models.Comment{}).
+		Select("project_id, COUNT(*) AS count").
+		Where("project_id IN ?", ids).
+		Group("project_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ProjectID] = row.Count
+	}
+	return counts, nil
+}
+
+// WriteUserExportZip writes the export as a ZIP archive with one JSON file per section
+func WriteUserExportZip(w io.Writer, export *UserExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"identities.json", export.Identities},
+		{"sessions.json", export.Sessions},
+		{"projects.json", export.Projects},
+		{"articles.json", export.Articles},
+		{"comments.json", export.Comments},
+		{"likes.json", export.Likes},
+		{"transactions.json", export.Transactions},
+	}
+
+	for _, file := range files {
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/mailer"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// SendVerificationEmail emails the user a signed link to verify their address
+func SendVerificationEmail(user *models.User) error {
+	cfg := config.GetConfig()
+	ttl := time.Duration(cfg.Auth.EmailVerificationTTLHours) * time.Hour
+
+	token, err := utils.GenerateActionToken(user.ID, user.Email, utils.PurposeVerifyEmail, ttl)
+	if err != nil {
+		return fmt.Errorf("gagal membuat token verifikasi: %w", err)
+	}
+
+	link := cfg.FrontendBaseURL() + "/verify-email?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf(
+		"Halo %s,\n\nTerima kasih telah mendaftar di Campus Project Hub.\n"+
+			"Silakan verifikasi email Anda melalui tautan berikut:\n\n%s\n\n"+
+			"Tautan ini berlaku selama %d jam. Abaikan email ini jika Anda tidak merasa mendaftar.",
+		user.Name, link, cfg.Auth.EmailVerificationTTLHours,
+	)
+
+	return mailer.GetMailer().Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verifikasi email Campus Project Hub",
+		Body:    body,
+	})
+}
+
+// VerifyEmail marks the user's email as verified using a token from the verification email
+func VerifyEmail(token string) (*models.User, error) {
+	claims, err := utils.ValidateActionToken(token, utils.PurposeVerifyEmail)
+	if err != nil {
+		return nil, errors.New("token verifikasi tidak valid atau sudah kedaluwarsa")
+	}
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.First(&user, "id = ?", claims.UserID).Error; err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	// The token is bound to the address it was sent to
+	if user.Email != claims.Email {
+		return nil, errors.New("token verifikasi tidak valid atau sudah kedaluwarsa")
+	}
+
+	if user.IsEmailVerified() {
+		return &user, nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := db.Model(&user).Update("email_verified_at", now).Error; err != nil {
+		return nil, fmt.Errorf("gagal memverifikasi email: %w", err)
+	}
+
+	return &user, nil
+}
+
+// ResendVerificationEmail sends a new verification email to an unverified user
+func ResendVerificationEmail(userID uuid.UUID) error {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return errors.New("email sudah terverifikasi")
+	}
+
+	if err := SendVerificationEmail(user); err != nil {
+		return errors.New("gagal mengirim email verifikasi")
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/campus-project-hub/api/internal/config"
+)
+
+// useTestConfig replaces the loaded configuration for the duration of a test
+func useTestConfig(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	previous := config.AppConfig_
+	config.AppConfig_ = cfg
+	t.Cleanup(func() { config.AppConfig_ = previous })
+}
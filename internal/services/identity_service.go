@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IsSupportedOAuthProvider reports whether users can log in with the provider
+func IsSupportedOAuthProvider(provider string) bool {
+	return provider == OAuthProviderGoogle || provider == OAuthProviderGithub || isOIDCProvider(provider)
+}
+
+// OAuthLoginPath returns the route, relative to the API prefix, that starts a login with the provider
+func OAuthLoginPath(provider string) string {
+	if provider == OAuthProviderGoogle || provider == OAuthProviderGithub {
+		return "/auth/" + provider
+	}
+	return "/auth/oidc/" + provider
+}
+
+// GetProviderOAuthConfig returns the OAuth configuration of a supported provider
+func GetProviderOAuthConfig(provider string) (*oauth2.Config, error) {
+	switch provider {
+	case OAuthProviderGoogle:
+		return GetGoogleOAuthConfig(), nil
+	case OAuthProviderGithub:
+		return GetGitHubOAuthConfig(), nil
+	}
+	return GetOIDCOAuthConfig(provider)
+}
+
+func newIdentity(userID uuid.UUID, info *OAuthUserInfo) *models.UserIdentity {
+	return &models.UserIdentity{
+		UserID:         userID,
+		Provider:       info.Provider,
+		ProviderUserID: info.ID,
+		Email:          optionalString(info.Email),
+	}
+}
+
+// linkIdentityByEmail attaches a provider account to the user owning the same verified email
+func linkIdentityByEmail(user *models.User, info *OAuthUserInfo) error {
+	// Nobody proved ownership of the email before, so a password set at registration
+	// may belong to someone else. The provider becomes the login method instead.
+	wasUnverified := !user.IsEmailVerified()
+
+	db := database.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newIdentity(user.ID, info)).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if user.AvatarURL == nil || *user.AvatarURL == "" {
+			updates["avatar_url"] = info.AvatarURL
+			user.AvatarURL = &info.AvatarURL
+		}
+		if wasUnverified {
+			now := time.Now()
+			updates["email_verified_at"] = now
+			updates["password_hash"] = nil
+			user.EmailVerifiedAt = &now
+			user.PasswordHash = nil
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return fmt.Errorf("gagal menghubungkan akun: %w", err)
+	}
+
+	if wasUnverified {
+		if _, err := RevokeAllSessions(user.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListIdentities returns the provider accounts linked to the user
+func ListIdentities(userID uuid.UUID) ([]models.UserIdentity, error) {
+	db := database.GetDB()
+	var identities []models.UserIdentity
+	err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+// LinkIdentity attaches a provider account to a logged in user
+func LinkIdentity(userID uuid.UUID, info *OAuthUserInfo) error {
+	db := database.GetDB()
+
+	var existing models.UserIdentity
+	err := db.Where("provider = ? AND provider_user_id = ?", info.Provider, info.ID).First(&existing).Error
+	if err == nil {
+		if existing.UserID != userID {
+			return errors.New("akun ini sudah terhubung dengan pengguna lain")
+		}
+		return db.Model(&existing).Update("last_used_at", time.Now()).Error
+	}
+
+	if err := db.Create(newIdentity(userID, info)).Error; err != nil {
+		return fmt.Errorf("gagal menghubungkan akun: %w", err)
+	}
+	return nil
+}
+
+// UnlinkIdentity removes a provider account from the user, keeping at least one login method
+func UnlinkIdentity(userID, identityID uuid.UUID) error {
+	db := database.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return errors.New("user tidak ditemukan")
+		}
+
+		var identity models.UserIdentity
+		if err := tx.First(&identity, "id = ? AND user_id = ?", identityID, userID).Error; err != nil {
+			return errors.New("akun terhubung tidak ditemukan")
+		}
+
+		var count int64
+		if err := tx.Model(&models.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if user.PasswordHash == nil && count <= 1 {
+			return errors.New("tidak dapat melepas metode login terakhir, buat password terlebih dahulu")
+		}
+
+		return tx.Delete(&identity).Error
+	})
+}
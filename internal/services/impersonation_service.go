@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpersonationResult is the short-lived access token an admin uses to act as a user
+type ImpersonationResult struct {
+	User        *models.User
+	AccessToken string
+	ExpiresIn   int64
+}
+
+// StartImpersonation opens a session on the target user's account for the admin.
+// Only regular users can be impersonated, and the start is written to the audit log.
+func StartImpersonation(admin *models.User, targetID uuid.UUID, client ClientInfo) (*ImpersonationResult, error) {
+	target, err := GetUserByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.ID == admin.ID {
+		return nil, errors.New("tidak dapat melakukan impersonasi terhadap akun sendiri")
+	}
+	if target.Role != models.RoleUser {
+		return nil, errors.New("hanya akun user biasa yang dapat diimpersonasi")
+	}
+	if target.Status == models.StatusBlocked {
+		return nil, errors.New("akun user telah diblokir")
+	}
+
+	cfg := config.GetConfig()
+	ttl := time.Duration(cfg.Auth.ImpersonationTTLMinutes) * time.Minute
+
+	// The session never gets a refresh token, so it stores the hash of a random value
+	unusable, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat token: %w", err)
+	}
+
+	session := models.Session{
+		ID:               uuid.New(),
+		UserID:           target.ID,
+		RefreshTokenHash: utils.HashToken(unusable),
+		UserAgent:        optionalString(client.UserAgent),
+		IPAddress:        optionalString(client.IPAddress),
+		ExpiresAt:        time.Now().Add(ttl),
+		ImpersonatedBy:   &admin.ID,
+	}
+
+	token, err := utils.GenerateImpersonationToken(target.ID, target.Email, string(target.Role), session.ID, admin.ID, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat token: %w", err)
+	}
+
+	db := database.GetDB()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		return tx.Create(newAuditLog(admin.ID, models.AuditImpersonationStart, "user", &target.ID, client)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulai impersonasi: %w", err)
+	}
+
+	return &ImpersonationResult{
+		User:        target,
+		AccessToken: token,
+		ExpiresIn:   int64(ttl.Seconds()),
+	}, nil
+}
+
+// StopImpersonation ends an impersonation session and writes the stop to the audit log
+func StopImpersonation(sessionID uuid.UUID, client ClientInfo) error {
+	db := database.GetDB()
+
+	var session models.Session
+	if err := db.First(&session, "id = ? AND impersonated_by IS NOT NULL", sessionID).Error; err != nil {
+		return errors.New("sesi impersonasi tidak ditemukan")
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("id = ? AND revoked_at IS NULL", session.ID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		// Already ended, nothing new to record
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Create(newAuditLog(*session.ImpersonatedBy, models.AuditImpersonationStop, "user", &session.UserID, client)).Error
+	})
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/models"
+	"gorm.io/gorm"
+)
+
+// loginAttemptPruneInterval is how often stale counters are removed
+const loginAttemptPruneInterval = 10 * time.Minute
+
+// LoginAttemptStore keeps the failed login counters. The in-memory store is enough
+// for a single instance; deployments with several instances share the Postgres store.
+type LoginAttemptStore interface {
+	// Get returns the counter for the key, or nil when there is none
+	Get(key string) (*models.LoginAttempt, error)
+	// RecordFailure counts a failed login. The count starts over when the previous
+	// counter has been quiet for longer than the window.
+	RecordFailure(key string, window time.Duration) (*models.LoginAttempt, error)
+	// Lock rejects logins for the key until the given time
+	Lock(key string, until time.Time) error
+	// Clear removes the counter and any lockout
+	Clear(key string) error
+}
+
+// MemoryLoginAttemptStore keeps counters in process memory
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]*models.LoginAttempt
+	window    time.Duration
+	lastPrune time.Time
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]*models.LoginAttempt)}
+}
+
+func (s *MemoryLoginAttemptStore) Get(key string) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+func (s *MemoryLoginAttemptStore) RecordFailure(key string, window time.Duration) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.window = window
+	s.prune(now)
+
+	attempt, ok := s.attempts[key]
+	if !ok || attempt.IsStale(now, window) {
+		attempt = &models.LoginAttempt{Key: key}
+		s.attempts[key] = attempt
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+
+	copied := *attempt
+	return &copied, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempt, ok := s.attempts[key]; ok {
+		attempt.LockedUntil = &until
+	}
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) Clear(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// prune drops stale counters so the map does not grow without bound. Callers hold the lock.
+func (s *MemoryLoginAttemptStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < loginAttemptPruneInterval {
+		return
+	}
+	s.lastPrune = now
+
+	for key, attempt := range s.attempts {
+		if attempt.IsStale(now, s.window) {
+			delete(s.attempts, key)
+		}
+	}
+}
+
+// PostgresLoginAttemptStore keeps counters in the login_attempts table
+type PostgresLoginAttemptStore struct {
+	db        *gorm.DB
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func NewPostgresLoginAttemptStore(db *gorm.DB) *PostgresLoginAttemptStore {
+	return &PostgresLoginAttemptStore{db: db}
+}
+
+func (s *PostgresLoginAttemptStore) Get(key string) (*models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	if err := s.db.Where("key = ?", key).Limit(1).Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	if len(attempts) == 0 {
+		return nil, nil
+	}
+	return &attempts[0], nil
+}
+
+func (s *PostgresLoginAttemptStore) RecordFailure(key string, window time.Duration) (*models.LoginAttempt, error) {
+	now := time.Now()
+	staleBefore := now.Add(-window)
+	s.prune(now, staleBefore)
+
+	// A single upsert keeps the count correct when several instances record at once
+	var attempt models.LoginAttempt
+	err := s.db.Raw(`
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN GREATEST(login_attempts.last_failure_at, COALESCE(login_attempts.locked_until, login_attempts.last_failure_at)) < ?
+				THEN 1 ELSE login_attempts.failures + 1 END,
+			locked_until = CASE
+				WHEN GREATEST(login_attempts.last_failure_at, COALESCE(login_attempts.locked_until, login_attempts.last_failure_at)) < ?
+				THEN NULL ELSE login_attempts.locked_until END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING key, failures, last_failure_at, locked_until`,
+		key, now, staleBefore, staleBefore,
+	).Scan(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (s *PostgresLoginAttemptStore) Lock(key string, until time.Time) error {
+	return s.db.Model(&models.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (s *PostgresLoginAttemptStore) Clear(key string) error {
+	return s.db.Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+// prune deletes stale rows at most once per interval on each instance
+func (s *PostgresLoginAttemptStore) prune(now, staleBefore time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < loginAttemptPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	s.db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", staleBefore, staleBefore).
+		Delete(&models.LoginAttempt{})
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+)
+
+// LoginLockedError is returned while an account or IP address is locked out
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "terlalu banyak percobaan login gagal, silakan coba lagi nanti"
+}
+
+// RetryAfterSeconds rounds the remaining lockout up to whole seconds for the Retry-After header
+func (e *LoginLockedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+var (
+	loginAttemptStore     LoginAttemptStore
+	loginAttemptStoreOnce sync.Once
+)
+
+func getLoginAttemptStore() LoginAttemptStore {
+	loginAttemptStoreOnce.Do(func() {
+		cfg := config.GetConfig()
+		if cfg.Auth.LoginAttemptStore == "postgres" {
+			loginAttemptStore = NewPostgresLoginAttemptStore(database.GetDB())
+		} else {
+			loginAttemptStore = NewMemoryLoginAttemptStore()
+		}
+	})
+	return loginAttemptStore
+}
+
+type loginAttemptKey struct {
+	key         string
+	maxAttempts int
+}
+
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginAttemptKeys returns the counters a login attempt is tracked under
+func loginAttemptKeys(email string, client ClientInfo) []loginAttemptKey {
+	cfg := config.GetConfig()
+	keys := []loginAttemptKey{{accountAttemptKey(email), cfg.Auth.LoginMaxAttempts}}
+	if client.IPAddress != "" {
+		keys = append(keys, loginAttemptKey{ipAttemptKey(client.IPAddress), cfg.Auth.LoginMaxAttemptsPerIP})
+	}
+	return keys
+}
+
+// checkLoginAllowed returns a LoginLockedError when the account or the IP address is locked out
+func checkLoginAllowed(email string, client ClientInfo) error {
+	store := getLoginAttemptStore()
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for _, k := range loginAttemptKeys(email, client) {
+		attempt, err := store.Get(k.key)
+		if err != nil {
+			log.Printf("Failed to read login attempts for %s: %v", k.key, err)
+			continue
+		}
+		if attempt != nil && attempt.IsLocked(now) {
+			retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt and locks the account or IP address once it
+// reaches its threshold. Every further failure doubles the lockout up to the configured maximum.
+func recordLoginFailure(email string, client ClientInfo) {
+	cfg := config.GetConfig()
+	store := getLoginAttemptStore()
+	window := time.Duration(cfg.Auth.LoginAttemptWindowMinutes) * time.Minute
+
+	for _, k := range loginAttemptKeys(email, client) {
+		attempt, err := store.RecordFailure(k.key, window)
+		if err != nil {
+			log.Printf("Failed to record login attempt for %s: %v", k.key, err)
+			continue
+		}
+		if attempt.Failures < k.maxAttempts {
+			continue
+		}
+
+		lockout := lockoutDuration(attempt.Failures - k.maxAttempts)
+		if err := store.Lock(k.key, attempt.LastFailureAt.Add(lockout)); err != nil {
+			log.Printf("Failed to lock %s: %v", k.key, err)
+		}
+	}
+}
+
+// recordLoginSuccess resets the account counter. The IP counter is left alone so an
+// attacker cannot reset it by logging into an account of their own.
+func recordLoginSuccess(email string) {
+	if err := getLoginAttemptStore().Clear(accountAttemptKey(email)); err != nil {
+		log.Printf("Failed to reset login attempts for %s: %v", email, err)
+	}
+}
+
+func lockoutDuration(excessFailures int) time.Duration {
+	cfg := config.GetConfig()
+	base := time.Duration(cfg.Auth.LoginLockoutBaseSeconds) * time.Second
+	maxLockout := time.Duration(cfg.Auth.LoginLockoutMaxMinutes) * time.Minute
+
+	// Cap the exponent before shifting so the duration cannot overflow
+	lockout := base << min(excessFailures, 20)
+	if lockout <= 0 || lockout > maxLockout {
+		return maxLockout
+	}
+	return lockout
+}
+
+// ClearLoginLockout removes the failed login counters for an account and, when given, an IP address
+func ClearLoginLockout(email, ip string) error {
+	store := getLoginAttemptStore()
+	if err := store.Clear(accountAttemptKey(email)); err != nil {
+		return err
+	}
+	if ip != "" {
+		return store.Clear(ipAttemptKey(ip))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	useTestConfig(t, &config.Config{Auth: config.AuthConfig{
+		LoginLockoutBaseSeconds: 30,
+		LoginLockoutMaxMinutes:  15,
+	}})
+
+	tests := []struct {
+		excessFailures int
+		want           time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 15 * time.Minute},
+		{20, 15 * time.Minute},
+		{64, 15 * time.Minute},
+		{1 << 30, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := lockoutDuration(tt.excessFailures); got != tt.want {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", tt.excessFailures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginLockedErrorRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       int
+	}{
+		{30 * time.Second, 30},
+		{29*time.Second + time.Millisecond, 30},
+		{time.Nanosecond, 1},
+	}
+	for _, tt := range tests {
+		err := &LoginLockedError{RetryAfter: tt.retryAfter}
+		if got := err.RetryAfterSeconds(); got != tt.want {
+			t.Errorf("RetryAfterSeconds() for %v = %d, want %d", tt.retryAfter, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryLoginAttemptStoreWindow(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+	const key = "account:budi@kampus.ac.id"
+
+	for i := 1; i <= 3; i++ {
+		attempt, err := store.RecordFailure(key, time.Hour)
+		if err != nil {
+			t.Fatalf("RecordFailure() error = %v", err)
+		}
+		if attempt.Failures != i {
+			t.Fatalf("Failures = %d, want %d", attempt.Failures, i)
+		}
+	}
+
+	// A counter quiet for longer than the window starts over
+	store.attempts[key].LastFailureAt = time.Now().Add(-2 * time.Hour)
+	attempt, err := store.RecordFailure(key, time.Hour)
+	if err != nil {
+		t.Fatalf("RecordFailure() error = %v", err)
+	}
+	if attempt.Failures != 1 {
+		t.Errorf("Failures after the window = %d, want 1", attempt.Failures)
+	}
+}
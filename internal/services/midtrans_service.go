@@ -14,6 +14,8 @@ import (
 	"github.com/campus-project-hub/api/internal/database"
 	"github.com/campus-project-hub/api/internal/models"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MidtransSnapRequest for creating transaction
@@ -73,7 +75,7 @@ func CreateSnapTransaction(projectID, buyerID uuid.UUID, buyerEmail, buyerName s
 
 	// Get project
 	var project models.Project
-	if err := db.Preload("User").First(&project, "id = ?", projectID).Error; err != nil {
+	if err := db.Preload("User").First(&project, "id = ?", projectID).Error; err != nil || !project.IsPublished() {
 		return nil, nil, fmt.Errorf("project tidak ditemukan")
 	}
 
@@ -99,7 +101,22 @@ func CreateSnapTransaction(projectID, buyerID uuid.UUID, buyerEmail, buyerName s
 		MidtransOrderID: &orderID,
 	}
 
-	if err := db.Create(&transaction).Error; err != nil {
+	// The revenue split is fixed when the purchase starts
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+		shares, err := splitSale(tx, &project, &transaction)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&shares).Error; err != nil {
+			return err
+		}
+		transaction.Shares = shares
+		return nil
+	})
+	if err != nil {
 		return nil, nil, fmt.Errorf("gagal membuat transaksi: %w", err)
 	}
 
@@ -175,7 +192,7 @@ func HandleMidtransNotification(notification *MidtransNotification) error {
 
 	// Find transaction
 	var transaction models.Transaction
-	if err := db.Where("midtrans_order_id = ?", notification.OrderID).First(&transaction).Error; err != nil {
+	if err := db.Preload("Shares").Where("midtrans_order_id = ?", notification.OrderID).First(&transaction).Error; err != nil {
 		return fmt.Errorf("transaksi tidak ditemukan")
 	}
 
@@ -187,9 +204,11 @@ func HandleMidtransNotification(notification *MidtransNotification) error {
 		if notification.FraudStatus == "accept" || notification.FraudStatus == "" {
 			transaction.Status = models.TransactionStatusSuccess
 
-			// Add EXP to buyer and seller
+			// Add EXP to buyer and every owner sharing in the sale
 			AddUserExp(transaction.BuyerID, ExpBuyProject)
-			AddUserExp(transaction.SellerID, ExpSellProject)
+			for _, share := range transaction.Shares {
+				AddUserExp(share.UserID, ExpSellProject)
+			}
 		}
 	case "pending":
 		transaction.Status = models.TransactionStatusPending
@@ -197,7 +216,7 @@ func HandleMidtransNotification(notification *MidtransNotification) error {
 		transaction.Status = models.TransactionStatusFailed
 	}
 
-	return db.Save(&transaction).Error
+	return db.Omit(clause.Associations).Save(&transaction).Error
 }
 
 func truncateString(s string, maxLen int) string {
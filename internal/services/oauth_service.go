@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/campus-project-hub/api/internal/config"
 	"github.com/campus-project-hub/api/internal/database"
@@ -15,6 +17,7 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
 	"golang.org/x/oauth2/google"
+	"gorm.io/gorm"
 )
 
 // OAuth provider types
@@ -25,19 +28,23 @@ const (
 
 // OAuthUserInfo represents user info from OAuth providers
 type OAuthUserInfo struct {
-	ID        string
-	Email     string
-	Name      string
-	AvatarURL string
-	Provider  string
+	ID            string
+	Email         string
+	EmailVerified bool
+	Name          string
+	AvatarURL     string
+	University    string
+	Major         string
+	Provider      string
 }
 
 // GoogleUserInfo from Google API
 type GoogleUserInfo struct {
-	ID      string `json:"id"`
-	Email   string `json:"email"`
-	Name    string `json:"name"`
-	Picture string `json:"picture"`
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	VerifiedEmail bool   `json:"verified_email"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
 }
 
 // GitHubUserInfo from GitHub API
@@ -84,10 +91,10 @@ func GetGitHubOAuthConfig() *oauth2.Config {
 }
 
 // GetGoogleUserInfo fetches user info from Google
-func GetGoogleUserInfo(code string) (*OAuthUserInfo, error) {
+func GetGoogleUserInfo(code, verifier string) (*OAuthUserInfo, error) {
 	oauthConfig := GetGoogleOAuthConfig()
 
-	token, err := oauthConfig.Exchange(context.Background(), code)
+	token, err := oauthConfig.Exchange(context.Background(), code, oauth2.VerifierOption(verifier))
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange code: %w", err)
 	}
@@ -110,19 +117,20 @@ func GetGoogleUserInfo(code string) (*OAuthUserInfo, error) {
 	}
 
 	return &OAuthUserInfo{
-		ID:        googleUser.ID,
-		Email:     googleUser.Email,
-		Name:      googleUser.Name,
-		AvatarURL: googleUser.Picture,
-		Provider:  OAuthProviderGoogle,
+		ID:            googleUser.ID,
+		Email:         googleUser.Email,
+		EmailVerified: googleUser.VerifiedEmail,
+		Name:          googleUser.Name,
+		AvatarURL:     googleUser.Picture,
+		Provider:      OAuthProviderGoogle,
 	}, nil
 }
 
 // GetGitHubUserInfo fetches user info from GitHub
-func GetGitHubUserInfo(code string) (*OAuthUserInfo, error) {
+func GetGitHubUserInfo(code, verifier string) (*OAuthUserInfo, error) {
 	oauthConfig := GetGitHubOAuthConfig()
 
-	token, err := oauthConfig.Exchange(context.Background(), code)
+	token, err := oauthConfig.Exchange(context.Background(), code, oauth2.VerifierOption(verifier))
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange code: %w", err)
 	}
@@ -146,13 +154,10 @@ func GetGitHubUserInfo(code string) (*OAuthUserInfo, error) {
 		return nil, fmt.Errorf("failed to parse user info: %w", err)
 	}
 
-	// Get primary email if not public
-	email := githubUser.Email
-	if email == "" {
-		email, err = getGitHubPrimaryEmail(client)
-		if err != nil {
-			return nil, err
-		}
+	// The profile email may be missing or unverified, so check it against the email list
+	email, verified, err := getGitHubEmail(client, githubUser.Email)
+	if err != nil {
+		return nil, err
 	}
 
 	name := githubUser.Name
@@ -161,106 +166,117 @@ func GetGitHubUserInfo(code string) (*OAuthUserInfo, error) {
 	}
 
 	return &OAuthUserInfo{
-		ID:        fmt.Sprintf("%d", githubUser.ID),
-		Email:     email,
-		Name:      name,
-		AvatarURL: githubUser.AvatarURL,
-		Provider:  OAuthProviderGithub,
+		ID:            fmt.Sprintf("%d", githubUser.ID),
+		Email:         email,
+		EmailVerified: verified,
+		Name:          name,
+		AvatarURL:     githubUser.AvatarURL,
+		Provider:      OAuthProviderGithub,
 	}, nil
 }
 
-func getGitHubPrimaryEmail(client *http.Client) (string, error) {
+// getGitHubEmail returns the public email, or the primary one, and whether GitHub verified it
+func getGitHubEmail(client *http.Client, publicEmail string) (string, bool, error) {
 	resp, err := client.Get("https://api.github.com/user/emails")
 	if err != nil {
-		return "", fmt.Errorf("failed to get emails: %w", err)
+		return "", false, fmt.Errorf("failed to get emails: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return "", false, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var emails []GitHubEmail
 	if err := json.Unmarshal(body, &emails); err != nil {
-		return "", fmt.Errorf("failed to parse emails: %w", err)
+		return "", false, fmt.Errorf("failed to parse emails: %w", err)
+	}
+
+	if publicEmail != "" {
+		for _, email := range emails {
+			if strings.EqualFold(email.Email, publicEmail) {
+				return publicEmail, email.Verified, nil
+			}
+		}
+		return publicEmail, false, nil
 	}
 
 	for _, email := range emails {
-		if email.Primary && email.Verified {
-			return email.Email, nil
+		if email.Primary {
+			return email.Email, email.Verified, nil
 		}
 	}
 
 	if len(emails) > 0 {
-		return emails[0].Email, nil
+		return emails[0].Email, emails[0].Verified, nil
 	}
 
-	return "", errors.New("no email found")
+	return "", false, errors.New("no email found")
 }
 
-// FindOrCreateOAuthUser finds existing user or creates new one
+// FindOrCreateOAuthUser returns the user linked to the provider account. An existing
+// account with the same email is only linked when the provider verified that email.
 func FindOrCreateOAuthUser(info *OAuthUserInfo) (*models.User, error) {
 	db := database.GetDB()
 
-	// Try to find by OAuth ID
-	var user models.User
-	err := db.Where("oauth_provider = ? AND oauth_id = ?", info.Provider, info.ID).First(&user).Error
+	// Try to find by linked identity
+	var identity models.UserIdentity
+	err := db.Preload("User").
+		Where("provider = ? AND provider_user_id = ?", info.Provider, info.ID).
+		First(&identity).Error
 	if err == nil {
-		return &user, nil
+		db.Model(&identity).Update("last_used_at", time.Now())
+		return &identity.User, nil
 	}
 
 	// Try to find by email
+	var user models.User
 	err = db.Where("email = ?", info.Email).First(&user).Error
 	if err == nil {
-		// Link OAuth to existing account
-		user.OAuthProvider = &info.Provider
-		user.OAuthID = &info.ID
-		if user.AvatarURL == nil || *user.AvatarURL == "" {
-			user.AvatarURL = &info.AvatarURL
+		if !info.EmailVerified {
+			return nil, errors.New("email sudah terdaftar, silakan login lalu hubungkan akun dari profil")
+		}
+		if err := linkIdentityByEmail(&user, info); err != nil {
+			return nil, err
 		}
-		db.Save(&user)
 		return &user, nil
 	}
 
-	// Create new user
+	// Create new user with its first identity
 	newUser := models.User{
-		Email:         info.Email,
-		Name:          info.Name,
-		AvatarURL:     &info.AvatarURL,
-		Role:          models.RoleUser,
-		Status:        models.StatusActive,
-		OAuthProvider: &info.Provider,
-		OAuthID:       &info.ID,
+		Email:     info.Email,
+		Name:      info.Name,
+		AvatarURL: &info.AvatarURL,
+		Role:      models.RoleUser,
+		Status:    models.StatusActive,
+		// Campus SSO providers can fill in the academic profile
+		University: optionalString(info.University),
+		Major:      optionalString(info.Major),
+	}
+	if info.EmailVerified {
+		now := time.Now()
+		newUser.EmailVerifiedAt = &now
 	}
 
-	if err := db.Create(&newUser).Error; err != nil {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newUser).Error; err != nil {
+			return err
+		}
+		return tx.Create(newIdentity(newUser.ID, info)).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	return &newUser, nil
 }
 
-// AuthResult for login/register
+// AuthResult for login/register. When TwoFactorRequired is set no tokens are issued
+// yet and the client must complete the login with ChallengeToken and a 2FA code.
 type AuthResult struct {
-	User      *models.User
-	TokenPair *utils.TokenPair
-}
-
-// AuthenticateOAuthUser authenticates or creates OAuth user and returns tokens
-func AuthenticateOAuthUser(info *OAuthUserInfo) (*AuthResult, error) {
-	user, err := FindOrCreateOAuthUser(info)
-	if err != nil {
-		return nil, err
-	}
-
-	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, string(user.Role))
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
-	}
-
-	return &AuthResult{
-		User:      user,
-		TokenPair: tokenPair,
-	}, nil
+	User              *models.User
+	TokenPair         *utils.TokenPair
+	TwoFactorRequired bool
+	ChallengeToken    string
 }
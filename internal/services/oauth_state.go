@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/utils"
+	"golang.org/x/oauth2"
+)
+
+// OAuthStateTTL is how long a user has to complete the provider login
+const OAuthStateTTL = 10 * time.Minute
+
+// OAuthState is kept in a signed cookie between the login redirect and the callback
+type OAuthState struct {
+	State     string `json:"s"`
+	Verifier  string `json:"v"`
+	Nonce     string `json:"n"`
+	Redirect  string `json:"r"`
+	ExpiresAt int64  `json:"e"`
+	// LinkUserID is set when a logged in user links a new provider instead of logging in
+	LinkUserID string `json:"l,omitempty"`
+}
+
+// NewOAuthState creates a random state and PKCE verifier for a new OAuth flow
+func NewOAuthState(redirect string) (*OAuthState, error) {
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthState{
+		State:     state,
+		Verifier:  oauth2.GenerateVerifier(),
+		Nonce:     nonce,
+		Redirect:  SanitizeRedirectPath(redirect),
+		ExpiresAt: time.Now().Add(OAuthStateTTL).Unix(),
+	}, nil
+}
+
+// AuthCodeURL builds the provider login URL carrying the state and PKCE challenge.
+// OpenID Connect flows also carry the nonce that the ID token must echo.
+func (s *OAuthState) AuthCodeURL(oauthConfig *oauth2.Config) string {
+	opts := []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(s.Verifier)}
+	if slices.Contains(oauthConfig.Scopes, "openid") {
+		opts = append(opts, oauth2.SetAuthURLParam("nonce", s.Nonce))
+	}
+	return oauthConfig.AuthCodeURL(s.State, opts...)
+}
+
+// Encode serializes and signs the state for storage in a cookie
+func (s *OAuthState) Encode() (string, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signOAuthState(encoded), nil
+}
+
+// DecodeOAuthState verifies a cookie value and checks it against the state returned by the provider
+func DecodeOAuthState(value, returnedState string) (*OAuthState, error) {
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("state tidak valid")
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(signOAuthState(parts[0]))) {
+		return nil, errors.New("state tidak valid")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("state tidak valid")
+	}
+
+	var state OAuthState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, errors.New("state tidak valid")
+	}
+
+	if time.Now().Unix() > state.ExpiresAt {
+		return nil, errors.New("sesi login sudah kedaluwarsa, silakan coba lagi")
+	}
+
+	if returnedState == "" || subtle.ConstantTimeCompare([]byte(state.State), []byte(returnedState)) != 1 {
+		return nil, errors.New("state tidak valid")
+	}
+
+	return &state, nil
+}
+
+func signOAuthState(payload string) string {
+	cfg := config.GetConfig()
+	mac := hmac.New(sha256.New, []byte(cfg.JWT.Secret))
+	mac.Write([]byte("oauth-state:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SanitizeRedirectPath only allows relative paths on the frontend, falling back to "/"
+func SanitizeRedirectPath(redirect string) string {
+	if redirect == "" || !strings.HasPrefix(redirect, "/") {
+		return "/"
+	}
+
+	// Reject protocol-relative URLs and backslash tricks such as "//evil.com" or "/\evil.com"
+	if strings.HasPrefix(redirect, "//") || strings.ContainsAny(redirect, "\\\r\n\t") {
+		return "/"
+	}
+
+	return redirect
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+)
+
+func useOAuthStateConfig(t *testing.T, secret string) {
+	t.Helper()
+	useTestConfig(t, &config.Config{JWT: config.JWTConfig{Secret: secret}})
+}
+
+func TestDecodeOAuthState(t *testing.T) {
+	useOAuthStateConfig(t, "state-secret")
+
+	state, err := NewOAuthState("/projects/new")
+	if err != nil {
+		t.Fatalf("NewOAuthState() error = %v", err)
+	}
+	state.LinkUserID = "3f1c7a52-8c1e-4c55-9b5e-2f8f3e0a6d11"
+	value, err := state.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	decoded, err := DecodeOAuthState(value, state.State)
+	if err != nil {
+		t.Fatalf("DecodeOAuthState() error = %v", err)
+	}
+	if *decoded != *state {
+		t.Errorf("DecodeOAuthState() = %+v, want %+v", *decoded, *state)
+	}
+
+	payload, signature, _ := strings.Cut(value, ".")
+	otherState, err := NewOAuthState("/")
+	if err != nil {
+		t.Fatalf("NewOAuthState() error = %v", err)
+	}
+	otherValue, err := otherState.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	otherPayload, _, _ := strings.Cut(otherValue, ".")
+
+	tests := []struct {
+		name     string
+		value    string
+		returned string
+	}{
+		{"state returned by the provider differs", value, otherState.State},
+		{"no state returned by the provider", value, ""},
+		{"unsigned cookie", payload, state.State},
+		{"tampered signature", payload + "." + signature[:len(signature)-2] + "xx", state.State},
+		{"payload swapped under the signature", otherPayload + "." + signature, otherState.State},
+		{"garbage", "not-a-state", state.State},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeOAuthState(tt.value, tt.returned); err == nil {
+				t.Error("DecodeOAuthState() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestDecodeOAuthStateRejectsOtherSecret(t *testing.T) {
+	useOAuthStateConfig(t, "old-secret")
+	state, err := NewOAuthState("/")
+	if err != nil {
+		t.Fatalf("NewOAuthState() error = %v", err)
+	}
+	value, err := state.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	useOAuthStateConfig(t, "new-secret")
+	if _, err := DecodeOAuthState(value, state.State); err == nil {
+		t.Error("DecodeOAuthState() accepted a state signed with another secret")
+	}
+}
+
+func TestDecodeOAuthStateExpired(t *testing.T) {
+	useOAuthStateConfig(t, "state-secret")
+
+	state, err := NewOAuthState("/")
+	if err != nil {
+		t.Fatalf("NewOAuthState() error = %v", err)
+	}
+	state.ExpiresAt = time.Now().Add(-time.Second).Unix()
+	value, err := state.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	_, err = DecodeOAuthState(value, state.State)
+	if err == nil || !strings.Contains(err.Error(), "kedaluwarsa") {
+		t.Errorf("DecodeOAuthState() error = %v, want expiry error", err)
+	}
+}
+
+func TestSanitizeRedirectPath(t *testing.T) {
+	tests := []struct {
+		redirect string
+		want     string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/dashboard", "/dashboard"},
+		{"/projects/abc?tab=files#top", "/projects/abc?tab=files#top"},
+		{"https://evil.com", "/"},
+		{"evil.com/path", "/"},
+		{"//evil.com", "/"},
+		{"///evil.com", "/"},
+		{"/\\evil.com", "/"},
+		{"/\\/evil.com", "/"},
+		{"/path\r\nSet-Cookie: a=b", "/"},
+		{"/\tevil.com", "/"},
+		{"javascript:alert(1)", "/"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeRedirectPath(tt.redirect); got != tt.want {
+			t.Errorf("SanitizeRedirectPath(%q) = %q, want %q", tt.redirect, got, tt.want)
+		}
+	}
+}
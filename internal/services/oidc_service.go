@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcDiscoveryTTL is how long discovery documents and signing keys are cached
+	oidcDiscoveryTTL = time.Hour
+	// oidcKeyRefreshInterval limits JWKS refetches when a token uses an unknown key
+	oidcKeyRefreshInterval = time.Minute
+)
+
+// OIDCProviderInfo lets clients render a login button for a configured provider
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	LoginURL    string `json:"loginUrl"`
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProvider struct {
+	discovery     oidcDiscovery
+	discoveredAt  time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+var (
+	oidcMu         sync.Mutex
+	oidcProviders  = map[string]*oidcProvider{}
+	oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// ListOIDCProviders returns the configured OIDC providers
+func ListOIDCProviders() []OIDCProviderInfo {
+	cfg := config.GetConfig()
+	providers := make([]OIDCProviderInfo, len(cfg.OAuth.OIDC))
+	for i, p := range cfg.OAuth.OIDC {
+		providers[i] = OIDCProviderInfo{
+			Name:        p.Name,
+			DisplayName: p.DisplayName,
+			LoginURL:    "/api/v1" + OAuthLoginPath(p.Name),
+		}
+	}
+	return providers
+}
+
+func isOIDCProvider(name string) bool {
+	cfg := config.GetConfig()
+	_, ok := cfg.OAuth.OIDCProvider(name)
+	return ok
+}
+
+// GetOIDCOAuthConfig returns the OAuth configuration of an OIDC provider using its discovery document
+func GetOIDCOAuthConfig(name string) (*oauth2.Config, error) {
+	cfg := config.GetConfig()
+	providerConfig, ok := cfg.OAuth.OIDCProvider(name)
+	if !ok {
+		return nil, errors.New("provider tidak ditemukan")
+	}
+
+	provider, err := discoverOIDCProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     providerConfig.ClientID,
+		ClientSecret: providerConfig.ClientSecret,
+		RedirectURL:  providerConfig.RedirectURL,
+		Scopes:       providerConfig.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.discovery.AuthorizationEndpoint,
+			TokenURL: provider.discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+// GetOIDCUserInfo exchanges the code, verifies the ID token and maps the claims to a user profile
+func GetOIDCUserInfo(name, code string, state *OAuthState) (*OAuthUserInfo, error) {
+	cfg := config.GetConfig()
+	providerConfig, ok := cfg.OAuth.OIDCProvider(name)
+	if !ok {
+		return nil, errors.New("provider tidak ditemukan")
+	}
+
+	oauthConfig, err := GetOIDCOAuthConfig(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, oidcHTTPClient)
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("id token missing from token response")
+	}
+
+	claims, err := verifyIDToken(providerConfig, rawIDToken, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some providers only put profile claims in the userinfo response
+	provider, err := discoverOIDCProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	if provider.discovery.UserinfoEndpoint != "" {
+		userinfo, err := fetchOIDCUserinfo(oauthConfig.Client(ctx, token), provider.discovery.UserinfoEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		if sub, _ := userinfo["sub"].(string); sub != "" && sub != claims["sub"] {
+			return nil, errors.New("userinfo subject does not match id token")
+		}
+		for key, value := range userinfo {
+			if _, exists := claims[key]; !exists {
+				claims[key] = value
+			}
+		}
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+
+	email := stringClaim(claims, providerConfig.Claims.Email)
+	if email == "" {
+		return nil, errors.New("no email found")
+	}
+
+	userName := stringClaim(claims, providerConfig.Claims.Name)
+	if userName == "" {
+		userName = email
+	}
+
+	return &OAuthUserInfo{
+		ID:            subject,
+		Email:         email,
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Name:          userName,
+		AvatarURL:     stringClaim(claims, "picture"),
+		University:    stringClaim(claims, providerConfig.Claims.University),
+		Major:         stringClaim(claims, providerConfig.Claims.Major),
+		Provider:      providerConfig.Name,
+	}, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token
+func verifyIDToken(providerConfig *config.OIDCProviderConfig, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	provider, err := discoverOIDCProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return oidcSigningKey(providerConfig, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(provider.discovery.Issuer),
+		jwt.WithAudience(providerConfig.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+func discoverOIDCProvider(providerConfig *config.OIDCProviderConfig) (*oidcProvider, error) {
+	oidcMu.Lock()
+	defer oidcMu.Unlock()
+
+	if provider, ok := oidcProviders[providerConfig.Name]; ok && time.Since(provider.discoveredAt) < oidcDiscoveryTTL {
+		return provider, nil
+	}
+
+	discoveryURL := providerConfig.DiscoveryURL
+	if !strings.Contains(discoveryURL, "/.well-known/") {
+		discoveryURL = strings.TrimRight(discoveryURL, "/") + "/.well-known/openid-configuration"
+	}
+
+	var discovery oidcDiscovery
+	if err := getJSON(oidcHTTPClient, discoveryURL, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to load discovery document: %w", err)
+	}
+	if discovery.Issuer == "" || discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("incomplete discovery document")
+	}
+
+	provider := &oidcProvider{discovery: discovery, discoveredAt: time.Now()}
+	oidcProviders[providerConfig.Name] = provider
+	return provider, nil
+}
+
+// oidcSigningKey returns the provider key with the given ID, refetching the JWKS when the key is unknown
+func oidcSigningKey(providerConfig *config.OIDCProviderConfig, kid string) (crypto.PublicKey, error) {
+	provider, err := discoverOIDCProvider(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcMu.Lock()
+	defer oidcMu.Unlock()
+
+	if key, ok := findOIDCKey(provider.keys, kid); ok {
+		return key, nil
+	}
+	if time.Since(provider.keysFetchedAt) < oidcKeyRefreshInterval {
+		return nil, errors.New("unknown signing key")
+	}
+
+	keys, err := fetchJWKS(provider.discovery.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	provider.keys = keys
+	provider.keysFetchedAt = time.Now()
+
+	if key, ok := findOIDCKey(keys, kid); ok {
+		return key, nil
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// findOIDCKey looks up a key by ID. Tokens without a kid are accepted when the set has a single key.
+func findOIDCKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if key, ok := keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func fetchJWKS(jwksURI string) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(oidcHTTPClient, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func fetchOIDCUserinfo(client *http.Client, endpoint string) (map[string]interface{}, error) {
+	var userinfo map[string]interface{}
+	if err := getJSON(client, endpoint, &userinfo); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	return userinfo, nil
+}
+
+func getJSON(client *http.Client, url string, dest interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// stringClaim reads a claim by name. Nested claims can be addressed with dots, e.g. "campus.major".
+func stringClaim(claims map[string]interface{}, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	var value interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[part]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		if len(v) > 0 {
+			if s, ok := v[0].(string); ok {
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	return ""
+}
+
+// boolClaim reads a boolean claim, accepting "true" strings sent by some providers
+func boolClaim(claims map[string]interface{}, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testOIDCProvider = "campus"
+	testOIDCClientID = "client-id"
+	testOIDCCode     = "valid-code"
+)
+
+// oidcTestServer is a minimal OpenID provider serving discovery, JWKS, token and userinfo endpoints
+type oidcTestServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	keys         map[string]*rsa.PrivateKey
+	jwksFetches  int
+	idToken      string
+	codeVerifier string
+}
+
+func newOIDCTestServer(t *testing.T) *oidcTestServer {
+	t.Helper()
+
+	s := &oidcTestServer{keys: map[string]*rsa.PrivateKey{"k1": newTestRSAKey(t)}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"userinfo_endpoint":      s.URL + "/userinfo",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.jwksFetches++
+		keys := []jsonWebKey{}
+		for kid, key := range s.keys {
+			keys = append(keys, jsonWebKey{
+				Kid: kid,
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		writeTestJSON(w, map[string]interface{}{"keys": keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != testOIDCCode {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.codeVerifier = r.PostForm.Get("code_verifier")
+		writeTestJSON(w, map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     s.idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeTestJSON(w, map[string]string{"sub": "user-1", "major": "Informatika"})
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	useTestConfig(t, &config.Config{
+		OAuth: config.OAuthConfig{OIDC: []config.OIDCProviderConfig{s.providerConfig()}},
+	})
+
+	// Start every test with an empty discovery and key cache
+	oidcMu.Lock()
+	previous := oidcProviders
+	oidcProviders = map[string]*oidcProvider{}
+	oidcMu.Unlock()
+	t.Cleanup(func() {
+		oidcMu.Lock()
+		oidcProviders = previous
+		oidcMu.Unlock()
+	})
+
+	return s
+}
+
+func (s *oidcTestServer) providerConfig() config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:         testOIDCProvider,
+		DiscoveryURL: s.URL,
+		ClientID:     testOIDCClientID,
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/campus/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+		Claims:       config.OIDCClaimMapping{Name: "name", Email: "email", Major: "major"},
+	}
+}
+
+// validClaims returns the claims of an ID token the test provider would issue
+func (s *oidcTestServer) validClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            s.URL,
+		"aud":            testOIDCClientID,
+		"sub":            "user-1",
+		"email":          "budi@kampus.ac.id",
+		"email_verified": true,
+		"name":           "Budi Santoso",
+		"nonce":          nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// sign signs the claims with the published key kid
+func (s *oidcTestServer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	s.mu.Lock()
+	key := s.keys[kid]
+	s.mu.Unlock()
+	return signTestToken(t, kid, key, claims)
+}
+
+// rotateKeys replaces the published key set with a single new key
+func (s *oidcTestServer) rotateKeys(t *testing.T, kid string) {
+	t.Helper()
+
+	key := newTestRSAKey(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = map[string]*rsa.PrivateKey{kid: key}
+}
+
+// receivedVerifier returns the PKCE verifier sent with the last token request
+func (s *oidcTestServer) receivedVerifier() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.codeVerifier
+}
+
+func (s *oidcTestServer) fetches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jwksFetches
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return key
+}
+
+func signTestToken(t *testing.T, kid string, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func writeTestJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	server := newOIDCTestServer(t)
+	providerConfig := server.providerConfig()
+	const nonce = "expected-nonce"
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr string
+	}{
+		{
+			name:  "valid token",
+			token: func() string { return server.sign(t, "k1", server.validClaims(nonce)) },
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := server.validClaims(nonce)
+				claims["iss"] = "https://evil.example.com"
+				return server.sign(t, "k1", claims)
+			},
+			wantErr: "issuer",
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := server.validClaims(nonce)
+				claims["aud"] = "another-client"
+				return server.sign(t, "k1", claims)
+			},
+			wantErr: "audience",
+		},
+		{
+			name: "nonce mismatch",
+			token: func() string {
+				return server.sign(t, "k1", server.validClaims("nonce-of-another-flow"))
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "missing nonce",
+			token: func() string {
+				claims := server.validClaims(nonce)
+				delete(claims, "nonce")
+				return server.sign(t, "k1", claims)
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := server.validClaims(nonce)
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return server.sign(t, "k1", claims)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "missing expiry",
+			token: func() string {
+				claims := server.validClaims(nonce)
+				delete(claims, "exp")
+				return server.sign(t, "k1", claims)
+			},
+			wantErr: "exp",
+		},
+		{
+			name: "bad signature",
+			token: func() string {
+				// Signed by an attacker's key under the provider's key ID
+				return signTestToken(t, "k1", newTestRSAKey(t), server.validClaims(nonce))
+			},
+			wantErr: "signature",
+		},
+		{
+			name: "symmetric algorithm",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, server.validClaims(nonce))
+				token.Header["kid"] = "k1"
+				signed, err := token.SignedString([]byte("client-secret"))
+				if err != nil {
+					t.Fatalf("sign token: %v", err)
+				}
+				return signed
+			},
+			wantErr: "signing method",
+		},
+		{
+			name: "unknown key",
+			token: func() string {
+				return signTestToken(t, "k9", newTestRSAKey(t), server.validClaims(nonce))
+			},
+			wantErr: "unknown signing key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifyIDToken(&providerConfig, tt.token(), nonce)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("verifyIDToken() error = %v", err)
+				}
+				if claims["sub"] != "user-1" {
+					t.Errorf("sub = %v, want user-1", claims["sub"])
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("verifyIDToken() succeeded, want error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verifyIDToken() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetOIDCUserInfo(t *testing.T) {
+	server := newOIDCTestServer(t)
+	state := &OAuthState{Verifier: oauth2.GenerateVerifier(), Nonce: "flow-nonce"}
+	server.idToken = server.sign(t, "k1", server.validClaims(state.Nonce))
+
+	info, err := GetOIDCUserInfo(testOIDCProvider, testOIDCCode, state)
+	if err != nil {
+		t.Fatalf("GetOIDCUserInfo() error = %v", err)
+	}
+
+	if got := server.receivedVerifier(); got != state.Verifier {
+		t.Errorf("token request code_verifier = %q, want the PKCE verifier of the state", got)
+	}
+	want := OAuthUserInfo{
+		ID:            "user-1",
+		Email:         "budi@kampus.ac.id",
+		EmailVerified: true,
+		Name:          "Budi Santoso",
+		Major:         "Informatika",
+		Provider:      testOIDCProvider,
+	}
+	if *info != want {
+		t.Errorf("GetOIDCUserInfo() = %+v, want %+v", *info, want)
+	}
+}
+
+func TestGetOIDCUserInfoRejectsTokenOfAnotherFlow(t *testing.T) {
+	server := newOIDCTestServer(t)
+	state := &OAuthState{Verifier: oauth2.GenerateVerifier(), Nonce: "flow-nonce"}
+	server.idToken = server.sign(t, "k1", server.validClaims("nonce-of-another-flow"))
+
+	if _, err := GetOIDCUserInfo(testOIDCProvider, testOIDCCode, state); err == nil {
+		t.Fatal("GetOIDCUserInfo() accepted an ID token with another flow's nonce")
+	}
+}
+
+func TestOIDCSigningKeyRotation(t *testing.T) {
+	server := newOIDCTestServer(t)
+	providerConfig := server.providerConfig()
+	const nonce = "n"
+
+	if _, err := verifyIDToken(&providerConfig, server.sign(t, "k1", server.validClaims(nonce)), nonce); err != nil {
+		t.Fatalf("token signed with k1: %v", err)
+	}
+	if got := server.fetches(); got != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", got)
+	}
+
+	server.rotateKeys(t, "k2")
+	rotated := server.sign(t, "k2", server.validClaims(nonce))
+
+	// Unknown key IDs refetch the JWKS at most once per refresh interval
+	if _, err := verifyIDToken(&providerConfig, rotated, nonce); err == nil {
+		t.Fatal("token signed with k2 accepted before the key set could be refreshed")
+	}
+	if got := server.fetches(); got != 1 {
+		t.Fatalf("JWKS fetched %d times within the refresh interval, want 1", got)
+	}
+
+	oidcMu.Lock()
+	oidcProviders[testOIDCProvider].keysFetchedAt = time.Now().Add(-oidcKeyRefreshInterval)
+	oidcMu.Unlock()
+
+	if _, err := verifyIDToken(&providerConfig, rotated, nonce); err != nil {
+		t.Fatalf("token signed with rotated key k2: %v", err)
+	}
+	if got := server.fetches(); got != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", got)
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/mailer"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RequestTransferInput names the new owner. Force is only honoured for admins and
+// transfers the resource at once without asking the recipient.
+type RequestTransferInput struct {
+	Username string `json:"username" validate:"required"`
+	Message  string `json:"message" validate:"max=1000"`
+	Force    bool   `json:"force"`
+}
+
+// TransferResource identifies the project or article to transfer and its current owner
+type TransferResource struct {
+	Type    models.TransferResourceType
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+	Title   string
+}
+
+// RequestOwnershipTransfer asks the recipient to take over the resource, or transfers it
+// right away when input.Force is set. Forced transfers are written to the audit log.
+func RequestOwnershipTransfer(resource TransferResource, requesterID uuid.UUID, input *RequestTransferInput, client ClientInfo) (*models.OwnershipTransfer, error) {
+	db := database.GetDB()
+
+	var recipient models.User
+	err := db.Where("username = ? AND status = ?", strings.ToLower(strings.TrimSpace(input.Username)), models.StatusActive).
+		First(&recipient).Error
+	if err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	if recipient.ID == resource.OwnerID {
+		return nil, errors.New("user tersebut sudah menjadi pemilik")
+	}
+
+	transfer := models.OwnershipTransfer{
+		ResourceType: resource.Type,
+		ResourceID:   resource.ID,
+		FromUserID:   resource.OwnerID,
+		ToUserID:     recipient.ID,
+		RequestedBy:  requesterID,
+		Status:       models.TransferPending,
+		Message:      optionalString(input.Message),
+	}
+
+	if input.Force {
+		now := time.Now()
+		transfer.Status = models.TransferForced
+		transfer.RespondedAt = &now
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// A forced transfer replaces any open request
+			if err := tx.Model(&models.OwnershipTransfer{}).
+				Where("resource_type = ? AND resource_id = ? AND status = ?", resource.Type, resource.ID, models.TransferPending).
+				Updates(map[string]interface{}{"status": models.TransferCancelled, "responded_at": now}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&transfer).Error; err != nil {
+				return err
+			}
+			if err := applyOwnershipTransfer(tx, &transfer); err != nil {
+				return err
+			}
+			return tx.Create(newAuditLog(requesterID, models.AuditOwnershipForced, string(resource.Type), &resource.ID, client)).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gagal memindahkan kepemilikan: %w", err)
+		}
+	} else {
+		var count int64
+		db.Model(&models.OwnershipTransfer{}).
+			Where("resource_type = ? AND resource_id = ? AND status = ?", resource.Type, resource.ID, models.TransferPending).
+			Count(&count)
+		if count > 0 {
+			return nil, errors.New("masih ada permintaan pemindahan yang menunggu jawaban")
+		}
+		if err := db.Create(&transfer).Error; err != nil {
+			return nil, fmt.Errorf("gagal membuat permintaan pemindahan: %w", err)
+		}
+	}
+
+	sendTransferEmail(recipient, resource, transfer.Status)
+
+	db.Preload("FromUser").Preload("ToUser").First(&transfer, "id = ?", transfer.ID)
+	return &transfer, nil
+}
+
+func sendTransferEmail(recipient models.User, resource TransferResource, status models.TransferStatus) {
+	go func() {
+		var body string
+		if status == models.TransferForced {
+			body = fmt.Sprintf(
+				"Halo %s,\n\nAdmin Campus Project Hub telah memindahkan kepemilikan %s \"%s\" kepada Anda.",
+				recipient.Name, resource.Type, resource.Title,
+			)
+		} else {
+			body = fmt.Sprintf(
+				"Halo %s,\n\nAnda diminta untuk menjadi pemilik baru %s \"%s\" di Campus Project Hub.\n"+
+					"Buka tautan berikut untuk menerima atau menolak permintaan ini:\n\n%s",
+				recipient.Name, resource.Type, resource.Title, config.GetConfig().FrontendBaseURL()+"/transfers",
+			)
+		}
+		if err := mailer.GetMailer().Send(mailer.Message{
+			To:      recipient.Email,
+			Subject: "Pemindahan kepemilikan di Campus Project Hub",
+			Body:    body,
+		}); err != nil {
+			log.Printf("Failed to send ownership transfer email to %s: %v", recipient.Email, err)
+		}
+	}()
+}
+
+// AcceptOwnershipTransfer makes the recipient the owner of the resource
+func AcceptOwnershipTransfer(transferID, userID uuid.UUID) error {
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var transfer models.OwnershipTransfer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&transfer, "id = ? AND to_user_id = ? AND status = ?", transferID, userID, models.TransferPending).Error; err != nil {
+			return errors.New("permintaan pemindahan tidak ditemukan")
+		}
+
+		if err := applyOwnershipTransfer(tx, &transfer); err != nil {
+			return err
+		}
+		return settleTransfer(tx, &transfer, models.TransferAccepted)
+	})
+}
+
+// DeclineOwnershipTransfer lets the recipient turn down a request
+func DeclineOwnershipTransfer(transferID, userID uuid.UUID) error {
+	return closeTransfer(database.GetDB().Where("to_user_id = ?", userID), transferID, models.TransferDeclined)
+}
+
+// CancelOwnershipTransfer lets the owner or the requester withdraw a request
+func CancelOwnershipTransfer(transferID, userID uuid.UUID) error {
+	return closeTransfer(database.GetDB().Where("from_user_id = ? OR requested_by = ?", userID, userID), transferID, models.TransferCancelled)
+}
+
+func closeTransfer(scope *gorm.DB, transferID uuid.UUID, status models.TransferStatus) error {
+	result := database.GetDB().Model(&models.OwnershipTransfer{}).
+		Where("id = ? AND status = ?", transferID, models.TransferPending).
+		Where(scope).
+		Updates(map[string]interface{}{"status": status, "responded_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("permintaan pemindahan tidak ditemukan")
+	}
+	return nil
+}
+
+func settleTransfer(tx *gorm.DB, transfer *models.OwnershipTransfer, status models.TransferStatus) error {
+	return tx.Model(transfer).Updates(map[string]interface{}{"status": status, "responded_at": time.Now()}).Error
+}
+
+// applyOwnershipTransfer moves the resource from transfer.FromUserID to transfer.ToUserID.
+// It fails when the resource is gone or changed hands since the request was made.
+func applyOwnershipTransfer(tx *gorm.DB, transfer *models.OwnershipTransfer) error {
+	switch transfer.ResourceType {
+	case models.TransferResourceProject:
+		var project models.Project
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&project, "id = ? AND user_id = ?", transfer.ResourceID, transfer.FromUserID).Error; err != nil {
+			return errors.New("project tidak ditemukan atau sudah berpindah pemilik")
+		}
+		return transferProject(tx, &project, transfer.ToUserID)
+	case models.TransferResourceArticle:
+		result := tx.Model(&models.Article{}).
+			Where("id = ? AND user_id = ?", transfer.ResourceID, transfer.FromUserID).
+			Update("user_id", transfer.ToUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("artikel tidak ditemukan atau sudah berpindah pemilik")
+		}
+		return nil
+	}
+	return errors.New("jenis resource tidak dikenal")
+}
+
+// transferProject hands the project to a new author. The project moves to the new owner's
+// URLs with the old one redirecting, the new owner takes over the previous author's revenue
+// share and the previous author stays on the team as maintainer. Past transactions keep
+// their seller and split; sales started from now on pay the new owner.
+func transferProject(tx *gorm.DB, project *models.Project, newOwnerID uuid.UUID) error {
+	previousOwnerID := project.UserID
+	oldSlug := project.Slug
+
+	slug, err := models.GenerateProjectSlug(tx, newOwnerID, project.Title, project.ID)
+	if err != nil {
+		return err
+	}
+	if err := moveSlug(tx, models.SlugResourceProject, newOwnerID, project.ID, "", slug); err != nil {
+		return err
+	}
+	if err := tx.Create(&models.SlugRedirect{
+		ResourceType: models.SlugResourceProject,
+		ScopeID:      previousOwnerID,
+		Slug:         oldSlug,
+		ResourceID:   project.ID,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(project).Updates(map[string]interface{}{"user_id": newOwnerID, "slug": slug}).Error; err != nil {
+		return err
+	}
+	project.UserID = newOwnerID
+	project.Slug = slug
+
+	var previous models.ProjectCollaborator
+	share := 100
+	if err := tx.Where("project_id = ? AND user_id = ?", project.ID, previousOwnerID).First(&previous).Error; err == nil {
+		share = previous.RevenueShare
+		if err := tx.Model(&previous).Updates(map[string]interface{}{
+			"role":          models.CollaboratorMaintainer,
+			"revenue_share": 0,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	var next models.ProjectCollaborator
+	if err := tx.Where("project_id = ? AND user_id = ?", project.ID, newOwnerID).First(&next).Error; err == nil {
+		updates := map[string]interface{}{
+			"role":          models.CollaboratorOwner,
+			"status":        models.CollaboratorAccepted,
+			"revenue_share": next.RevenueShare + share,
+		}
+		if next.AcceptedAt == nil {
+			updates["accepted_at"] = now
+		}
+		return tx.Model(&next).Updates(updates).Error
+	}
+	return tx.Create(&models.ProjectCollaborator{
+		ProjectID:    project.ID,
+		UserID:       newOwnerID,
+		Role:         models.CollaboratorOwner,
+		Status:       models.CollaboratorAccepted,
+		RevenueShare: share,
+		AcceptedAt:   &now,
+	}).Error
+}
+
+// ListOwnershipTransfers returns the transfers the user sent or received, newest first
+func ListOwnershipTransfers(userID uuid.UUID) ([]models.OwnershipTransfer, error) {
+	var transfers []models.OwnershipTransfer
+	err := database.GetDB().Preload("FromUser").Preload("ToUser").
+		Where("from_user_id = ? OR to_user_id = ? OR requested_by = ?", userID, userID, userID).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// ListResourceTransfers returns the ownership history of a project or article, newest first
+func ListResourceTransfers(resourceType models.TransferResourceType, resourceID uuid.UUID) ([]models.OwnershipTransfer, error) {
+	var transfers []models.OwnershipTransfer
+	err := database.GetDB().Preload("FromUser").Preload("ToUser").
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// TransferTitles looks up the titles of the transferred projects and articles by resource ID
+func TransferTitles(transfers []models.OwnershipTransfer) map[uuid.UUID]string {
+	var projectIDs, articleIDs []uuid.UUID
+	for _, transfer := range transfers {
+		if transfer.ResourceType == models.TransferResourceProject {
+			projectIDs = append(projectIDs, transfer.ResourceID)
+		} else {
+			articleIDs = append(articleIDs, transfer.ResourceID)
+		}
+	}
+
+	type titleRow struct {
+		ID    uuid.UUID
+		Title string
+	}
+	var rows []titleRow
+	db := database.GetDB()
+	if len(projectIDs) > 0 {
+		var projects []titleRow
+		db.Model(&models.Project{}).Select("id, title").Where("id IN ?", projectIDs).Scan(&projects)
+		rows = append(rows, projects...)
+	}
+	if len(articleIDs) > 0 {
+		var articles []titleRow
+		db.Model(&models.Article{}).Select("id, title").Where("id IN ?", articleIDs).Scan(&articles)
+		rows = append(rows, articles...)
+	}
+
+	titles := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		titles[row.ID] = row.Title
+	}
+	return titles
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/mailer"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ForgotPasswordInput for requesting a reset email
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordInput for setting a new password with a reset token
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// ChangePasswordInput for changing the password of a logged-in user.
+// CurrentPassword may be empty for OAuth-only accounts setting their first password.
+// RevokeTokens also revokes every personal access token of the user.
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8"`
+	RevokeTokens    bool   `json:"revokeTokens"`
+}
+
+// RequestPasswordReset emails a reset link if the account exists.
+// It never reveals whether the email is registered.
+func RequestPasswordReset(input *ForgotPasswordInput) error {
+	cfg := config.GetConfig()
+	db := database.GetDB()
+
+	var user models.User
+	if err := db.Where("email = ?", strings.TrimSpace(input.Email)).First(&user).Error; err != nil {
+		return nil
+	}
+	if user.Status == models.StatusBlocked {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return fmt.Errorf("gagal membuat token reset: %w", err)
+	}
+
+	ttl := time.Duration(cfg.Auth.PasswordResetTTLMinutes) * time.Minute
+	resetToken := models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	// Only the most recent reset link stays valid
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&resetToken).Error
+	}); err != nil {
+		return fmt.Errorf("gagal membuat token reset: %w", err)
+	}
+
+	link := cfg.FrontendBaseURL() + "/reset-password?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf(
+		"Halo %s,\n\nKami menerima permintaan untuk mengatur ulang password akun Campus Project Hub Anda.\n"+
+			"Gunakan tautan berikut untuk membuat password baru:\n\n%s\n\n"+
+			"Tautan ini hanya dapat digunakan sekali dan berlaku selama %d menit. "+
+			"Abaikan email ini jika Anda tidak meminta reset password.",
+		user.Name, link, cfg.Auth.PasswordResetTTLMinutes,
+	)
+
+	go func(to string) {
+		if err := mailer.GetMailer().Send(mailer.Message{
+			To:      to,
+			Subject: "Reset password Campus Project Hub",
+			Body:    body,
+		}); err != nil {
+			log.Printf("Failed to send password reset email to %s: %v", to, err)
+		}
+	}(user.Email)
+
+	return nil
+}
+
+// ResetPassword sets a new password with a single-use reset token and signs out every session
+func ResetPassword(input *ResetPasswordInput) error {
+	db := database.GetDB()
+	tokenHash := utils.HashToken(input.Token)
+
+	var resetToken models.PasswordResetToken
+	if err := db.First(&resetToken, "token_hash = ?", tokenHash).Error; err != nil {
+		return errors.New("token reset tidak valid atau sudah kedaluwarsa")
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return fmt.Errorf("gagal memproses password: %w", err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Consume the token atomically so it can only be used once
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", resetToken.ID, time.Now()).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("token reset tidak valid atau sudah kedaluwarsa")
+		}
+
+		return tx.Model(&models.User{}).Where("id = ?", resetToken.UserID).
+			Update("password_hash", hashedPassword).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := RevokeAllSessions(resetToken.UserID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi: %w", err)
+	}
+
+	// Tokens created by whoever had access to the account stop working as well
+	if _, err := RevokeAllPersonalAccessTokens(resetToken.UserID); err != nil {
+		return fmt.Errorf("gagal mencabut token akses: %w", err)
+	}
+
+	return nil
+}
+
+// ChangePassword updates the password of a logged-in user and signs out their other sessions,
+// optionally revoking their personal access tokens too
+func ChangePassword(userID, currentSessionID uuid.UUID, input *ChangePasswordInput) error {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// Accounts created through OAuth have no password yet and may set one directly
+	if user.PasswordHash != nil && !utils.CheckPassword(*user.PasswordHash, input.CurrentPassword) {
+		return errors.New("password saat ini salah")
+	}
+
+	hashedPassword, err := utils.HashPassword(input.NewPassword)
+	if err != nil {
+		return fmt.Errorf("gagal memproses password: %w", err)
+	}
+
+	db := database.GetDB()
+	if err := db.Model(user).Update("password_hash", hashedPassword).Error; err != nil {
+		return fmt.Errorf("gagal memperbarui password: %w", err)
+	}
+
+	if _, err := RevokeOtherSessions(userID, currentSessionID); err != nil {
+		return fmt.Errorf("gagal mencabut sesi: %w", err)
+	}
+
+	if input.RevokeTokens {
+		if _, err := RevokeAllPersonalAccessTokens(userID); err != nil {
+			return fmt.Errorf("gagal mencabut token akses: %w", err)
+		}
+	}
+
+	return nil
+}
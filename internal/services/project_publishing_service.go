@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApplyProjectStatus moves a project to the status its author asked for. Asking to publish
+// with a publishAt in the future schedules the project instead.
+func ApplyProjectStatus(project *models.Project, requested models.ProjectStatus) error {
+	if project.Status == models.ProjectStatusBlocked {
+		return errors.New("project diblokir oleh moderator dan statusnya tidak dapat diubah")
+	}
+
+	switch requested {
+	case models.ProjectStatusDraft:
+		project.Status = models.ProjectStatusDraft
+	case models.ProjectStatusPendingReview:
+		project.Status = models.ProjectStatusPendingReview
+		project.ReviewReason = nil
+	case models.ProjectStatusPublished:
+		publishOrSchedule(project, time.Now())
+	default:
+		return errors.New("status project tidak valid")
+	}
+	return nil
+}
+
+// publishOrSchedule publishes the project now or, when publishAt lies ahead, schedules it.
+// A project that is already live stays live.
+func publishOrSchedule(project *models.Project, now time.Time) {
+	if !project.IsPublished() && project.PublishAt != nil && project.PublishAt.After(now) {
+		project.Status = models.ProjectStatusScheduled
+		return
+	}
+
+	project.Status = models.ProjectStatusPublished
+	if project.PublishedAt == nil {
+		project.PublishedAt = &now
+	}
+}
+
+// ApproveProject publishes a project waiting for review, or schedules it when the author chose a later publishAt
+func ApproveProject(projectID, reviewerID uuid.UUID) (*models.Project, error) {
+	project, err := getProjectForReview(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	publishOrSchedule(project, now)
+	project.ReviewedBy = &reviewerID
+	project.ReviewedAt = &now
+	project.ReviewReason = nil
+
+	if err := database.GetDB().Save(project).Error; err != nil {
+		return nil, fmt.Errorf("gagal menyetujui project: %w", err)
+	}
+	return project, nil
+}
+
+// RejectProject sends a project back to its author with the reason
+func RejectProject(projectID, reviewerID uuid.UUID, reason string) (*models.Project, error) {
+	project, err := getProjectForReview(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	project.Status = models.ProjectStatusRejected
+	project.ReviewedBy = &reviewerID
+	project.ReviewedAt = &now
+	project.ReviewReason = &reason
+
+	if err := database.GetDB().Save(project).Error; err != nil {
+		return nil, fmt.Errorf("gagal menolak project: %w", err)
+	}
+	return project, nil
+}
+
+func getProjectForReview(projectID uuid.UUID) (*models.Project, error) {
+	var project models.Project
+	if err := database.GetDB().Preload("User").First(&project, "id = ?", projectID).Error; err != nil {
+		return nil, errors.New("project tidak ditemukan")
+	}
+	if project.Status != models.ProjectStatusPendingReview {
+		return nil, errors.New("project tidak sedang menunggu review")
+	}
+	return &project, nil
+}
+
+// PublishDueProjects publishes every scheduled project whose publishAt has passed
+func PublishDueProjects() (int, error) {
+	now := time.Now()
+	result := database.GetDB().Model(&models.Project{}).
+		Where("status = ? AND publish_at <= ?", models.ProjectStatusScheduled, now).
+		Updates(map[string]interface{}{
+			"status":       models.ProjectStatusPublished,
+			"published_at": gorm.Expr("COALESCE(published_at, ?)", now),
+		})
+	return int(result.RowsAffected), result.Error
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// releaseContentTypes lists the file types a release may hold
+var releaseContentTypes = map[string]string{
+	".zip": "application/zip",
+	".pdf": "application/pdf",
+}
+
+// CreateReleaseInput describes a new release. The files come from the multipart form.
+type CreateReleaseInput struct {
+	Version string `form:"version" validate:"required,max=50"`
+	Notes   string `form:"notes" validate:"max=5000"`
+}
+
+// releaseDir is the private directory holding a project's release files
+func releaseDir(projectID uuid.UUID) string {
+	return filepath.Join(config.GetConfig().Upload.PrivateDir, projectID.String())
+}
+
+func assetPath(projectID uuid.UUID, asset *models.ReleaseAsset) string {
+	return filepath.Join(releaseDir(projectID), asset.StoredName)
+}
+
+// ErrCreateReleaseFailed is returned when a release cannot be stored. The cause is
+// logged; any other CreateRelease error describes invalid input.
+var ErrCreateReleaseFailed = errors.New("gagal membuat rilis")
+
+// CreateRelease stores the files in private storage and records the release
+func CreateRelease(project *models.Project, creatorID uuid.UUID, input *CreateReleaseInput, files []*multipart.FileHeader) (*models.ProjectRelease, error) {
+	cfg := config.GetConfig()
+	db := database.GetDB()
+
+	if len(files) == 0 {
+		return nil, errors.New("minimal satu file diperlukan")
+	}
+
+	version := strings.TrimSpace(input.Version)
+	var count int64
+	db.Model(&models.ProjectRelease{}).Where("project_id = ? AND version = ?", project.ID, version).Count(&count)
+	if count > 0 {
+		return nil, errors.New("versi rilis sudah ada")
+	}
+
+	release := models.ProjectRelease{
+		ID:        uuid.New(),
+		ProjectID: project.ID,
+		Version:   version,
+		Notes:     optionalString(input.Notes),
+		CreatedBy: &creatorID,
+	}
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Filename))
+		contentType, ok := releaseContentTypes[ext]
+		if !ok {
+			return nil, errors.New("file rilis harus berupa ZIP atau PDF")
+		}
+		if file.Size > cfg.Upload.ReleaseMaxSize {
+			return nil, fmt.Errorf("ukuran file rilis maksimal %dMB", cfg.Upload.ReleaseMaxSize/1024/1024)
+		}
+
+		id := uuid.New()
+		release.Assets = append(release.Assets, models.ReleaseAsset{
+			ID:          id,
+			ReleaseID:   release.ID,
+			FileName:    filepath.Base(file.Filename),
+			StoredName:  id.String() + ext,
+			ContentType: contentType,
+			Size:        file.Size,
+		})
+	}
+
+	if err := os.MkdirAll(releaseDir(project.ID), 0700); err != nil {
+		log.Printf("Failed to create release directory for project %s: %v", project.ID, err)
+		return nil, ErrCreateReleaseFailed
+	}
+
+	// Files written before a failure are removed again
+	var written []string
+	cleanup := func() {
+		for _, path := range written {
+			os.Remove(path)
+		}
+	}
+	for i, file := range files {
+		path := assetPath(project.ID, &release.Assets[i])
+		if err := saveReleaseFile(file, path); err != nil {
+			cleanup()
+			log.Printf("Failed to store release file of project %s: %v", project.ID, err)
+			return nil, ErrCreateReleaseFailed
+		}
+		written = append(written, path)
+	}
+
+	if err := db.Create(&release).Error; err != nil {
+		cleanup()
+		log.Printf("Failed to create release of project %s: %v", project.ID, err)
+		return nil, ErrCreateReleaseFailed
+	}
+
+	return &release, nil
+}
+
+func saveReleaseFile(file *multipart.FileHeader, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func preloadAssetsInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC, file_name ASC")
+}
+
+// ListReleases returns the project's releases, newest first
+func ListReleases(projectID uuid.UUID) ([]models.ProjectRelease, error) {
+	var releases []models.ProjectRelease
+	err := database.GetDB().Preload("Assets", preloadAssetsInOrder).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC").
+		Find(&releases).Error
+	return releases, err
+}
+
+// GetRelease returns one release of the project with its assets
+func GetRelease(projectID, releaseID uuid.UUID) (*models.ProjectRelease, error) {
+	var release models.ProjectRelease
+	if err := database.GetDB().Preload("Assets", preloadAssetsInOrder).
+		First(&release, "id = ? AND project_id = ?", releaseID, projectID).Error; err != nil {
+		return nil, errors.New("rilis tidak ditemukan")
+	}
+	return &release, nil
+}
+
+// DeleteRelease removes the release and its files
+func DeleteRelease(release *models.ProjectRelease) error {
+	if err := database.GetDB().Delete(release).Error; err != nil {
+		return fmt.Errorf("gagal menghapus rilis: %w", err)
+	}
+	for i := range release.Assets {
+		os.Remove(assetPath(release.ProjectID, &release.Assets[i]))
+	}
+	return nil
+}
+
+// RemoveReleaseFiles deletes the stored files of all releases of a deleted project
+func RemoveReleaseFiles(projectID uuid.UUID) {
+	os.RemoveAll(releaseDir(projectID))
+}
+
+// CanDownloadRelease reports whether the user may download the project's release files:
+// its team, and for paid projects buyers with a successful transaction, who keep access
+// even when the project is hidden later. Releases of free projects are open to every
+// signed-in user while the project is published.
+// project.Collaborators must be preloaded.
+func CanDownloadRelease(project *models.Project, user *models.User) bool {
+	if user == nil {
+		return false
+	}
+	if _, member := project.CollaboratorRole(user.ID); member {
+		return true
+	}
+	if project.Type != models.ProjectTypePaid {
+		return project.IsPublished()
+	}
+
+	var count int64
+	database.GetDB().Model(&models.Transaction{}).
+		Where("project_id = ? AND buyer_id = ? AND status = ?", project.ID, user.ID, models.TransactionStatusSuccess).
+		Count(&count)
+	return count > 0
+}
+
+// errDownloadTokenUsed is returned for download URLs that were already used or have expired
+var errDownloadTokenUsed = errors.New("tautan unduhan sudah digunakan atau kedaluwarsa, minta tautan baru")
+
+// IssueDownloadURL signs a short-lived URL for the user to download the asset once
+func IssueDownloadURL(asset *models.ReleaseAsset, userID uuid.UUID) (string, time.Time, error) {
+	cfg := config.GetConfig()
+	ttl := time.Duration(cfg.Upload.DownloadTTLMinutes) * time.Minute
+
+	downloadToken := models.DownloadToken{
+		ID:        uuid.New(),
+		AssetID:   asset.ID,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	db := database.GetDB()
+	if err := db.Create(&downloadToken).Error; err != nil {
+		return "", time.Time{}, fmt.Errorf("gagal membuat tautan unduhan: %w", err)
+	}
+
+	// Opportunistically clean up tokens that can no longer be used
+	db.Where("expires_at < ?", time.Now().Add(-time.Hour)).Delete(&models.DownloadToken{})
+
+	token, err := utils.GenerateDownloadToken(userID, asset.ID, downloadToken.ID, ttl)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("gagal membuat tautan unduhan: %w", err)
+	}
+
+	url := strings.TrimRight(cfg.App.BaseURL, "/") + "/api/v1/downloads/" + token
+	return url, downloadToken.ExpiresAt, nil
+}
+
+// OpenDownload checks a signed download token, uses it up and counts the download. It
+// returns the asset and the path of its file in private storage.
+func OpenDownload(token string) (*models.ReleaseAsset, string, error) {
+	claims, err := utils.ValidateActionToken(token, utils.PurposeDownload)
+	if err != nil {
+		return nil, "", errors.New("tautan unduhan tidak valid atau sudah kedaluwarsa")
+	}
+	assetID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, "", errors.New("tautan unduhan tidak valid atau sudah kedaluwarsa")
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, "", errors.New("tautan unduhan tidak valid atau sudah kedaluwarsa")
+	}
+
+	db := database.GetDB()
+	var asset models.ReleaseAsset
+	if err := db.Preload("Release").First(&asset, "id = ?", assetID).Error; err != nil {
+		return nil, "", errors.New("file tidak ditemukan")
+	}
+
+	path := assetPath(asset.Release.ProjectID, &asset)
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", errors.New("file tidak ditemukan")
+	}
+
+	// Mark the token as used atomically so each URL downloads and counts once
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.DownloadToken{}).
+			Where("id = ? AND asset_id = ? AND used_at IS NULL AND expires_at > ?", tokenID, asset.ID, time.Now()).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errDownloadTokenUsed
+		}
+		return tx.Model(&asset).UpdateColumn("downloads", gorm.Expr("downloads + 1")).Error
+	})
+	if errors.Is(err, errDownloadTokenUsed) {
+		return nil, "", err
+	}
+	if err != nil {
+		log.Printf("Failed to use download token %s: %v", tokenID, err)
+		return nil, "", errors.New("gagal memproses unduhan, silakan coba lagi")
+	}
+
+	return &asset, path, nil
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PreloadImagesInOrder loads project images in the order the author arranged them
+func PreloadImagesInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order ASC")
+}
+
+// ReplaceProjectImages swaps the project's images for the given URLs and updates project.Images
+func ReplaceProjectImages(tx *gorm.DB, project *models.Project, imageURLs []string) error {
+	if err := tx.Delete(&models.ProjectImage{}, "project_id = ?", project.ID).Error; err != nil {
+		return err
+	}
+
+	images := make([]models.ProjectImage, len(imageURLs))
+	for i, imgURL := range imageURLs {
+		images[i] = models.ProjectImage{
+			ProjectID: project.ID,
+			ImageURL:  imgURL,
+			SortOrder: i,
+		}
+	}
+	if len(images) > 0 {
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+	}
+
+	project.Images = images
+	return nil
+}
+
+// RecordProjectRevision stores the project's current state as a new revision. before is
+// the state prior to the edit, or nil for a new project. Projects created before revision
+// history get that prior state as their first revision so the edit can still be undone.
+// Edits that change nothing are not recorded.
+func RecordProjectRevision(tx *gorm.DB, project *models.Project, before *models.ProjectSnapshot, editorID uuid.UUID, restoredFrom *int) error {
+	// Serialize revisions of the same project so numbers stay consecutive
+	var locked models.Project
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, "id = ?", project.ID).Error; err != nil {
+		return err
+	}
+
+	var latest int
+	if err := tx.Model(&models.ProjectRevision{}).Where("project_id = ?", project.ID).
+		Select("COALESCE(MAX(number), 0)").Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	after := project.Snapshot()
+	changedFields := []string{}
+	if before != nil {
+		if latest == 0 {
+			baseline := models.ProjectRevision{
+				ProjectID:     project.ID,
+				Number:        1,
+				ChangedFields: []string{},
+				Snapshot:      *before,
+			}
+			if err := tx.Create(&baseline).Error; err != nil {
+				return err
+			}
+			latest = 1
+		}
+
+		changedFields = after.ChangedFields(*before)
+		if len(changedFields) == 0 {
+			return nil
+		}
+	}
+
+	revision := models.ProjectRevision{
+		ProjectID:     project.ID,
+		Number:        latest + 1,
+		EditorID:      &editorID,
+		ChangedFields: changedFields,
+		RestoredFrom:  restoredFrom,
+		Snapshot:      after,
+	}
+	return tx.Create(&revision).Error
+}
+
+// ListProjectRevisions returns the project's revisions, newest first
+func ListProjectRevisions(projectID uuid.UUID) ([]models.ProjectRevision, error) {
+	var revisions []models.ProjectRevision
+	err := database.GetDB().Preload("Editor").
+		Where("project_id = ?", projectID).
+		Order("number DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetProjectRevision returns one revision of the project by its number
+func GetProjectRevision(projectID uuid.UUID, number int) (*models.ProjectRevision, error) {
+	var revision models.ProjectRevision
+	if err := database.GetDB().Preload("Editor").
+		First(&revision, "project_id = ? AND number = ?", projectID, number).Error; err != nil {
+		return nil, errors.New("revisi tidak ditemukan")
+	}
+	return &revision, nil
+}
+
+// RestoreProjectRevision puts the project back to the state of an earlier revision.
+// The restore is itself recorded as a new revision, so it can be undone as well.
+func RestoreProjectRevision(projectID uuid.UUID, number int, editorID uuid.UUID) (*models.Project, error) {
+	revision, err := GetProjectRevision(projectID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.GetDB()
+	var project models.Project
+	if err := db.Preload("Images", PreloadImagesInOrder).First(&project, "id = ?", projectID).Error; err != nil {
+		return nil, errors.New("project tidak ditemukan")
+	}
+
+	before := project.Snapshot()
+	revision.Snapshot.Apply(&project)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if project.Title != before.Title {
+			if err := RefreshProjectSlug(tx, &project); err != nil {
+				return err
+			}
+		}
+		// A restored publishAt may move a scheduled project
+		if project.Status == models.ProjectStatusScheduled {
+			if err := ApplyProjectStatus(&project, models.ProjectStatusPublished); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit(clause.Associations).Save(&project).Error; err != nil {
+			return err
+		}
+		if err := SetProjectTechnologies(tx, &project, revision.Snapshot.TechStack); err != nil {
+			return err
+		}
+		if err := ReplaceProjectImages(tx, &project, revision.Snapshot.Images); err != nil {
+			return err
+		}
+		return RecordProjectRevision(tx, &project, &before, editorID, &number)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal memulihkan revisi: %w", err)
+	}
+
+	return &project, nil
+}
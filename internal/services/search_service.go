@@ -0,0 +1,166 @@
+package services
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Matched words are wrapped in control characters by ts_headline and turned into <mark>
+// tags once the rest of the snippet has been escaped
+const (
+	highlightStart  = "\x01"
+	highlightStop   = "\x02"
+	headlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`
+)
+
+// Result types of Search
+const (
+	SearchTypeProject = "project"
+	SearchTypeArticle = "article"
+)
+
+func searchLanguage() string {
+	return config.GetConfig().Search.Language
+}
+
+// searchMatch returns a query selecting search_id and search_rank of the rows of table
+// (projects or articles) whose search vector matches q. The vector includes the author's
+// name at the lowest weight, and the GIN index on it serves the lookup.
+func searchMatch(table, q string) (string, []interface{}) {
+	lang := searchLanguage()
+	sql := `SELECT id AS search_id,
+		ts_rank(search_vector, websearch_to_tsquery(CAST(? AS regconfig), ?)) AS search_rank
+	FROM ` + table + `
+	WHERE search_vector @@ websearch_to_tsquery(CAST(? AS regconfig), ?)`
+	return sql, []interface{}{lang, q, lang, q}
+}
+
+// SearchScope keeps the rows of table matching the full-text query q and exposes their
+// relevance as search.search_rank for ordering
+func SearchScope(table, q string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sql, args := searchMatch(table, q)
+		return db.Joins("JOIN ("+sql+") AS search ON search.search_id = "+table+".id", args...)
+	}
+}
+
+// SearchRankOrder orders rows of a SearchScope query by relevance
+const SearchRankOrder = "search.search_rank DESC"
+
+// SearchResult is a published project or article matching a search. Snippet is an escaped
+// excerpt of the description or content with the matched words in <mark> tags.
+type SearchResult struct {
+	Type         string                    `json:"type"`
+	ID           uuid.UUID                 `json:"id"`
+	Title        string                    `json:"title"`
+	Slug         *string                   `json:"slug,omitempty"`
+	ThumbnailURL *string                   `json:"thumbnailUrl"`
+	Snippet      string                    `json:"snippet"`
+	Rank         float64                   `json:"rank"`
+	Author       models.PublicUserResponse `json:"author"`
+	CreatedAt    time.Time                 `json:"createdAt"`
+}
+
+type searchHit struct {
+	Type         string
+	ID           uuid.UUID
+	Title        string
+	Slug         *string
+	ThumbnailURL *string
+	UserID       uuid.UUID
+	CreatedAt    time.Time
+	Rank         float64
+	Snippet      string
+}
+
+// Search finds published projects and articles matching q, the most relevant first.
+// resultType limits the results to "project" or "article"; empty searches both.
+func Search(q, resultType string, page, perPage int) ([]SearchResult, int64, error) {
+	db := database.GetDB()
+	lang := searchLanguage()
+
+	var parts []string
+	var args []interface{}
+	if resultType == "" || resultType == SearchTypeProject {
+		sql, matchArgs := searchMatch("projects", q)
+		parts = append(parts, `SELECT 'project' AS type, p.id, p.title, p.slug, p.thumbnail_url,
+			p.description AS body, p.user_id, p.created_at, s.search_rank AS rank
+		FROM projects p JOIN (`+sql+`) s ON s.search_id = p.id
+		WHERE p.status = 'published'`)
+		args = append(args, matchArgs...)
+	}
+	if resultType == "" || resultType == SearchTypeArticle {
+		sql, matchArgs := searchMatch("articles", q)
+		parts = append(parts, `SELECT 'article' AS type, a.id, a.title, NULL AS slug, a.thumbnail_url,
+			coalesce(a.excerpt, '') || ' ' || coalesce(a.content, '') AS body, a.user_id, a.created_at, s.search_rank AS rank
+		FROM articles a JOIN (`+sql+`) s ON s.search_id = a.id
+		WHERE a.status = 'published'`)
+		args = append(args, matchArgs...)
+	}
+	hits := strings.Join(parts, " UNION ALL ")
+
+	var total int64
+	if err := db.Raw("SELECT COUNT(*) FROM ("+hits+") AS hits", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Snippets are only built for the requested page
+	pageArgs := append([]interface{}{lang, lang, q, headlineOptions}, args...)
+	pageArgs = append(pageArgs, perPage, (page-1)*perPage)
+	var rows []searchHit
+	err := db.Raw(`SELECT page.type, page.id, page.title, page.slug, page.thumbnail_url, page.user_id, page.created_at, page.rank,
+		ts_headline(CAST(? AS regconfig), coalesce(page.body, ''), websearch_to_tsquery(CAST(? AS regconfig), ?), ?) AS snippet
+	FROM (
+		SELECT * FROM (`+hits+`) AS hits
+		ORDER BY hits.rank DESC, hits.created_at DESC
+		LIMIT ? OFFSET ?
+	) AS page
+	ORDER BY page.rank DESC, page.created_at DESC`, pageArgs...).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	authorIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		authorIDs = append(authorIDs, row.UserID)
+	}
+	var authors []models.User
+	if len(authorIDs) > 0 {
+		db.Preload("VerifiedUniversity").Where("id IN ?", authorIDs).Find(&authors)
+	}
+	authorByID := make(map[uuid.UUID]*models.User, len(authors))
+	for i := range authors {
+		authorByID[authors[i].ID] = &authors[i]
+	}
+
+	results := make([]SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = SearchResult{
+			Type:         row.Type,
+			ID:           row.ID,
+			Title:        row.Title,
+			Slug:         row.Slug,
+			ThumbnailURL: row.ThumbnailURL,
+			Snippet:      highlightSnippet(row.Snippet),
+			Rank:         row.Rank,
+			CreatedAt:    row.CreatedAt,
+		}
+		if author, ok := authorByID[row.UserID]; ok {
+			results[i].Author = author.ToPublicResponse()
+		}
+	}
+
+	return results, total, nil
+}
+
+// highlightSnippet escapes a ts_headline snippet and marks the matched words
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(snippet))
+}
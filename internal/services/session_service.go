@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// ClientInfo describes the device a session was started from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// CreateSession starts a new login session and returns its token pair
+func CreateSession(user *models.User, client ClientInfo) (*utils.TokenPair, error) {
+	cfg := config.GetConfig()
+	db := database.GetDB()
+
+	session := models.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		UserAgent: optionalString(client.UserAgent),
+		IPAddress: optionalString(client.IPAddress),
+		ExpiresAt: time.Now().Add(time.Duration(cfg.JWT.RefreshExpiryHours) * time.Hour),
+	}
+
+	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, string(user.Role), session.ID)
+	if err != nil {
+		return nil, err
+	}
+	session.RefreshTokenHash = utils.HashToken(tokenPair.RefreshToken)
+
+	if err := db.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("gagal membuat sesi: %w", err)
+	}
+
+	return tokenPair, nil
+}
+
+// errRefreshTokenReused revokes the session it was detected on
+var errRefreshTokenReused = errors.New("refresh token telah digunakan, silakan login kembali")
+
+// RefreshTokens rotates the refresh token of a session and returns a new token pair.
+// Presenting a refresh token that has already been rotated revokes the whole session.
+func RefreshTokens(refreshToken string, client ClientInfo) (*utils.TokenPair, error) {
+	claims, err := utils.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	sessionID, err := claims.SessionID()
+	if err != nil {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	db := database.GetDB()
+	var session models.Session
+	if err := db.First(&session, "id = ?", sessionID).Error; err != nil {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	if err := checkRefreshSession(&session, refreshToken); err != nil {
+		if errors.Is(err, errRefreshTokenReused) {
+			RevokeSession(session.ID)
+		}
+		return nil, err
+	}
+
+	var user models.User
+	if err := db.First(&user, "id = ?", session.UserID).Error; err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+
+	if user.Status == models.StatusBlocked {
+		RevokeSession(session.ID)
+		return nil, errors.New("akun Anda telah diblokir")
+	}
+
+	tokenPair, err := utils.GenerateTokenPair(user.ID, user.Email, string(user.Role), session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat token: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"refresh_token_hash": utils.HashToken(tokenPair.RefreshToken),
+		"last_used_at":       time.Now(),
+	}
+	if client.IPAddress != "" {
+		updates["ip_address"] = client.IPAddress
+	}
+
+	// Only rotate if nobody else rotated the session in the meantime
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, session.RefreshTokenHash).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("gagal memperbarui sesi: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		RevokeSession(session.ID)
+		return nil, errRefreshTokenReused
+	}
+
+	return tokenPair, nil
+}
+
+// checkRefreshSession decides whether a refresh token may rotate its session. A token
+// that no longer matches the stored hash was already rotated, so it is being replayed.
+func checkRefreshSession(session *models.Session, refreshToken string) error {
+	if !session.IsActive() {
+		return errors.New("sesi telah berakhir, silakan login kembali")
+	}
+	if session.RefreshTokenHash != utils.HashToken(refreshToken) {
+		return errRefreshTokenReused
+	}
+	return nil
+}
+
+// RevokeSession revokes a single session
+func RevokeSession(sessionID uuid.UUID) error {
+	db := database.GetDB()
+	return db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// ListActiveSessions returns the user's sessions that are neither revoked nor expired.
+// Sessions opened by an impersonating admin are left out.
+func ListActiveSessions(userID uuid.UUID) ([]models.Session, error) {
+	db := database.GetDB()
+	var sessions []models.Session
+	err := db.Where("user_id = ? AND impersonated_by IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeUserSession revokes one of the user's own sessions
+func RevokeUserSession(userID, sessionID uuid.UUID) error {
+	db := database.GetDB()
+	result := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("gagal mencabut sesi: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("sesi tidak ditemukan")
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes every session of the user except the given one
+func RevokeOtherSessions(userID, keepSessionID uuid.UUID) (int64, error) {
+	db := database.GetDB()
+	result := db.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// RevokeAllSessions signs the user out everywhere
+func RevokeAllSessions(userID uuid.UUID) (int64, error) {
+	return RevokeOtherSessions(userID, uuid.Nil)
+}
+
+// IsSessionActive reports whether a session exists, belongs to the user and is not revoked
+func IsSessionActive(sessionID, userID uuid.UUID) bool {
+	db := database.GetDB()
+	var session models.Session
+	if err := db.First(&session, "id = ? AND user_id = ?", sessionID, userID).Error; err != nil {
+		return false
+	}
+	return session.IsActive()
+}
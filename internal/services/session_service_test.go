@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+)
+
+func TestCheckRefreshSession(t *testing.T) {
+	const current = "current-refresh-token"
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		session    models.Session
+		token      string
+		wantErr    bool
+		wantReused bool
+	}{
+		{
+			name:    "current token",
+			session: models.Session{RefreshTokenHash: utils.HashToken(current), ExpiresAt: time.Now().Add(time.Hour)},
+			token:   current,
+		},
+		{
+			name:       "rotated token replayed",
+			session:    models.Session{RefreshTokenHash: utils.HashToken(current), ExpiresAt: time.Now().Add(time.Hour)},
+			token:      "previous-refresh-token",
+			wantErr:    true,
+			wantReused: true,
+		},
+		{
+			name:    "revoked session",
+			session: models.Session{RefreshTokenHash: utils.HashToken(current), ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt},
+			token:   current,
+			wantErr: true,
+		},
+		{
+			name:    "expired session",
+			session: models.Session{RefreshTokenHash: utils.HashToken(current), ExpiresAt: time.Now().Add(-time.Second)},
+			token:   current,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRefreshSession(&tt.session, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRefreshSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if reused := errors.Is(err, errRefreshTokenReused); reused != tt.wantReused {
+				t.Errorf("reuse detected = %v, want %v", reused, tt.wantReused)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// changeUsername validates and sets a new username. The previous one is kept as a
+// redirect so profile and portfolio links shared before the change keep working.
+func changeUsername(tx *gorm.DB, user *models.User, username string) error {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if username == user.Username {
+		return nil
+	}
+
+	if len(username) < models.UsernameMinLength || len(username) > models.UsernameMaxLength || !utils.IsValidSlug(username) {
+		return fmt.Errorf("username harus %d-%d karakter berupa huruf kecil, angka atau tanda hubung", models.UsernameMinLength, models.UsernameMaxLength)
+	}
+
+	taken, err := models.UsernameTaken(tx, username, user.ID)
+	if err != nil {
+		return fmt.Errorf("gagal memeriksa username: %w", err)
+	}
+	if taken {
+		return errors.New("username sudah digunakan")
+	}
+
+	if err := moveSlug(tx, models.SlugResourceUser, uuid.Nil, user.ID, user.Username, username); err != nil {
+		return fmt.Errorf("gagal menyimpan riwayat username: %w", err)
+	}
+	user.Username = username
+	return nil
+}
+
+// RefreshProjectSlug regenerates the slug after the title changed and keeps the old slug as a redirect
+func RefreshProjectSlug(tx *gorm.DB, project *models.Project) error {
+	slug, err := models.GenerateProjectSlug(tx, project.UserID, project.Title, project.ID)
+	if err != nil {
+		return err
+	}
+	if slug == project.Slug {
+		return nil
+	}
+
+	if err := moveSlug(tx, models.SlugResourceProject, project.UserID, project.ID, project.Slug, slug); err != nil {
+		return err
+	}
+	project.Slug = slug
+	return nil
+}
+
+// moveSlug records the old slug as a redirect and drops the redirect for the new one,
+// which exists when a resource takes back a slug it used before
+func moveSlug(tx *gorm.DB, resourceType models.SlugResourceType, scopeID, resourceID uuid.UUID, oldSlug, newSlug string) error {
+	if err := tx.Where("resource_type = ? AND scope_id = ? AND slug = ?", resourceType, scopeID, newSlug).
+		Delete(&models.SlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if oldSlug == "" {
+		return nil
+	}
+
+	return tx.Create(&models.SlugRedirect{
+		ResourceType: resourceType,
+		ScopeID:      scopeID,
+		Slug:         oldSlug,
+		ResourceID:   resourceID,
+	}).Error
+}
+
+// ResolveUsername finds an active user by their current or a previous username.
+// moved is true when the username is an old one and the caller should redirect.
+func ResolveUsername(username string) (user *models.User, moved bool, err error) {
+	db := database.GetDB()
+	username = strings.ToLower(username)
+
+	var found models.User
+	err = db.Preload("VerifiedUniversity").Where("username = ? AND status <> ?", username, models.StatusDeleted).First(&found).Error
+	if err == nil {
+		return &found, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	var redirect models.SlugRedirect
+	err = db.Where("resource_type = ? AND scope_id = ? AND slug = ?", models.SlugResourceUser, uuid.Nil, username).
+		First(&redirect).Error
+	if err != nil {
+		return nil, false, errors.New("user tidak ditemukan")
+	}
+
+	user, err = GetUserByID(redirect.ResourceID)
+	if err != nil || user.IsDeleted() {
+		return nil, false, errors.New("user tidak ditemukan")
+	}
+	return user, true, nil
+}
+
+// ResolveProjectSlug finds the author's project by its current or a previous slug.
+// moved is true when the slug is an old one and the caller should redirect.
+func ResolveProjectSlug(userID uuid.UUID, slug string) (projectID uuid.UUID, moved bool, err error) {
+	db := database.GetDB()
+	slug = strings.ToLower(slug)
+
+	var ids []uuid.UUID
+	if err := db.Model(&models.Project{}).Where("user_id = ? AND slug = ?", userID, slug).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return uuid.Nil, false, err
+	}
+	if len(ids) > 0 {
+		return ids[0], false, nil
+	}
+
+	var redirect models.SlugRedirect
+	err = db.Where("resource_type = ? AND scope_id = ? AND slug = ?", models.SlugResourceProject, userID, slug).
+		First(&redirect).Error
+	if err != nil {
+		return uuid.Nil, false, errors.New("project tidak ditemukan")
+	}
+	return redirect.ResourceID, true, nil
+}
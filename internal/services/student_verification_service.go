@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/mailer"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// maxStudentVerificationAttempts limits guesses of the 6 digit code
+const maxStudentVerificationAttempts = 5
+
+// StudentVerificationInput starts verification of a campus email
+type StudentVerificationInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmStudentVerificationInput carries the code sent to the campus email
+type ConfirmStudentVerificationInput struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// FindUniversityByEmail returns the university whose domain allow-list covers the email
+func FindUniversityByEmail(email string) (*models.University, error) {
+	candidates := models.EmailDomainCandidates(email)
+	if len(candidates) == 0 {
+		return nil, errors.New("email tidak valid")
+	}
+
+	db := database.GetDB()
+	var university models.University
+	if err := db.Where("domains && ?", pq.StringArray(candidates)).First(&university).Error; err != nil {
+		return nil, errors.New("domain email kampus tidak terdaftar")
+	}
+	return &university, nil
+}
+
+// RequestStudentVerification emails a code to a campus address of a known university
+func RequestStudentVerification(userID uuid.UUID, input *StudentVerificationInput) error {
+	cfg := config.GetConfig()
+	db := database.GetDB()
+
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	university, err := FindUniversityByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	db.Model(&models.User{}).Where("LOWER(campus_email) = ? AND id <> ?", email, user.ID).Count(&count)
+	if count > 0 {
+		return errors.New("email kampus sudah digunakan akun lain")
+	}
+
+	code, err := generateNumericCode(6)
+	if err != nil {
+		return fmt.Errorf("gagal membuat kode verifikasi: %w", err)
+	}
+
+	verification := models.StudentVerification{
+		UserID:       user.ID,
+		Email:        email,
+		UniversityID: university.ID,
+		CodeHash:     utils.HashToken(code),
+		ExpiresAt:    time.Now().Add(time.Duration(cfg.Auth.StudentVerificationTTLMinutes) * time.Minute),
+	}
+
+	// Requesting a new code replaces the pending one
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.StudentVerification{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&verification).Error
+	}); err != nil {
+		return fmt.Errorf("gagal membuat verifikasi: %w", err)
+	}
+
+	body := fmt.Sprintf(
+		"Halo %s,\n\nGunakan kode berikut untuk memverifikasi status mahasiswa Anda di %s:\n\n%s\n\n"+
+			"Kode ini berlaku selama %d menit. Abaikan email ini jika Anda tidak merasa memintanya.",
+		user.Name, university.Name, code, cfg.Auth.StudentVerificationTTLMinutes,
+	)
+
+	if err := mailer.GetMailer().Send(mailer.Message{
+		To:      email,
+		Subject: "Kode verifikasi mahasiswa Campus Project Hub",
+		Body:    body,
+	}); err != nil {
+		return errors.New("gagal mengirim kode verifikasi")
+	}
+
+	return nil
+}
+
+// ConfirmStudentVerification checks the code and marks the user as a verified student
+func ConfirmStudentVerification(userID uuid.UUID, input *ConfirmStudentVerificationInput) (*models.User, error) {
+	db := database.GetDB()
+
+	var verification models.StudentVerification
+	if err := db.Preload("University").First(&verification, "user_id = ?", userID).Error; err != nil {
+		return nil, errors.New("tidak ada verifikasi yang sedang berjalan")
+	}
+
+	// Count the attempt before comparing so parallel guesses cannot exceed the limit
+	result := db.Model(&models.StudentVerification{}).
+		Where("id = ? AND attempts < ? AND expires_at > ?", verification.ID, maxStudentVerificationAttempts, time.Now()).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil || result.RowsAffected == 0 {
+		db.Delete(&verification)
+		return nil, errors.New("kode verifikasi sudah kedaluwarsa, silakan minta kode baru")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(verification.CodeHash), []byte(utils.HashToken(input.Code))) != 1 {
+		return nil, errors.New("kode verifikasi tidak valid")
+	}
+
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"campus_email":           verification.Email,
+			"verified_university_id": verification.UniversityID,
+			"student_verified_at":    now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&verification).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal memverifikasi status mahasiswa: %w", err)
+	}
+
+	var user models.User
+	if err := db.Preload("VerifiedUniversity").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, errors.New("user tidak ditemukan")
+	}
+	return &user, nil
+}
+
+// RemoveStudentVerification clears the verified student status of the user
+func RemoveStudentVerification(userID uuid.UUID) error {
+	db := database.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.StudentVerification{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"campus_email":           nil,
+			"verified_university_id": nil,
+			"student_verified_at":    nil,
+		}).Error
+	})
+}
+
+func generateNumericCode(digits int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
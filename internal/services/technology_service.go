@@ -0,0 +1,342 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// findTechnology looks up the technology a normalized name stands for. Names that only
+// differ by a trailing "js" ("reactjs") fall back to the technology without it.
+func findTechnology(tx *gorm.DB, key string) (*models.Technology, bool) {
+	candidates := []string{key}
+	if trimmed := strings.TrimSuffix(key, "js"); trimmed != key && trimmed != "" {
+		candidates = append(candidates, trimmed)
+	}
+
+	for _, candidate := range candidates {
+		var technology models.Technology
+		err := tx.Where("key = ? OR slug = ? OR ? = ANY(aliases)", candidate, models.TechnologySlug(candidate), candidate).
+			First(&technology).Error
+		if err == nil {
+			return &technology, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveTechnologies maps free-form tech stack names to canonical technologies, creating
+// entries for names that are not known yet. Duplicates are dropped and the order is kept.
+func ResolveTechnologies(tx *gorm.DB, names []string) ([]models.Technology, error) {
+	technologies := []models.Technology{}
+	seen := map[uuid.UUID]bool{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := models.TechnologyKey(name)
+		if key == "" || len(name) > 100 {
+			continue
+		}
+
+		technology, found := findTechnology(tx, key)
+		if !found {
+			created := models.Technology{
+				Name:    name,
+				Key:     key,
+				Slug:    models.TechnologySlug(key),
+				Aliases: pq.StringArray{},
+			}
+			// Another request may have added the same technology in the meantime
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			if result.RowsAffected == 0 {
+				if technology, found = findTechnology(tx, key); !found {
+					return nil, fmt.Errorf("technology %q could not be created", name)
+				}
+			} else {
+				technology = &created
+			}
+		}
+
+		if !seen[technology.ID] {
+			seen[technology.ID] = true
+			technologies = append(technologies, *technology)
+		}
+	}
+
+	return technologies, nil
+}
+
+// SetProjectTechnologies replaces the project's tech stack with the canonical names of the
+// given technologies and links them. The project must already be saved.
+func SetProjectTechnologies(tx *gorm.DB, project *models.Project, names []string) error {
+	technologies, err := ResolveTechnologies(tx, names)
+	if err != nil {
+		return err
+	}
+
+	techStack := make([]string, len(technologies))
+	for i, technology := range technologies {
+		techStack[i] = technology.Name
+	}
+	project.TechStack = techStack
+	if err := tx.Model(project).UpdateColumn("tech_stack", project.TechStack).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(project).Omit("Technologies.*").Association("Technologies").Replace(technologies); err != nil {
+		return err
+	}
+	project.Technologies = technologies
+	return nil
+}
+
+// FindTechnologyIDs resolves slugs, names or aliases to technology IDs without creating
+// anything. ok is false when one of the values is unknown.
+func FindTechnologyIDs(values []string) (ids []uuid.UUID, ok bool) {
+	db := database.GetDB()
+	for _, value := range values {
+		key := models.TechnologyKey(value)
+		if key == "" {
+			continue
+		}
+		technology, found := findTechnology(db, key)
+		if !found {
+			return nil, false
+		}
+		if !slices.Contains(ids, technology.ID) {
+			ids = append(ids, technology.ID)
+		}
+	}
+	return ids, true
+}
+
+// projectCountSQL counts the published projects using a technology
+const projectCountSQL = `(SELECT COUNT(*) FROM project_technologies
+	JOIN projects ON projects.id = project_technologies.project_id AND projects.status = 'published'
+	WHERE project_technologies.technology_id = technologies.id)`
+
+// SearchTechnologies returns technologies whose name or one of its aliases starts with
+// the query, the most used first. An empty query lists the most used technologies.
+func SearchTechnologies(q string, limit int) ([]models.Technology, error) {
+	db := database.GetDB()
+	query := db.Model(&models.Technology{})
+
+	if q = strings.TrimSpace(q); q != "" {
+		key := models.TechnologyKey(q)
+		query = query.Where(
+			"name ILIKE ? OR key LIKE ? OR EXISTS (SELECT 1 FROM unnest(aliases) AS alias WHERE alias LIKE ?)",
+			q+"%", key+"%", key+"%",
+		)
+	}
+
+	var technologies []models.Technology
+	if err := query.Order(projectCountSQL + " DESC, name ASC").Limit(limit).Find(&technologies).Error; err != nil {
+		return nil, err
+	}
+	countProjects(technologies)
+	return technologies, nil
+}
+
+// countProjects fills in the number of published projects using each technology
+func countProjects(technologies []models.Technology) {
+	if len(technologies) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(technologies))
+	for i := range technologies {
+		ids[i] = technologies[i].ID
+	}
+
+	var rows []struct {
+		TechnologyID uuid.UUID
+		Count        int
+	}
+	database.GetDB().Table("project_technologies").
+		Select("project_technologies.technology_id, COUNT(*) AS count").
+		Joins("JOIN projects ON projects.id = project_technologies.project_id AND projects.status = ?", models.ProjectStatusPublished).
+		Where("project_technologies.technology_id IN ?", ids).
+		Group("project_technologies.technology_id").
+		Scan(&rows)
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.TechnologyID] = row.Count
+	}
+	for i := range technologies {
+		technologies[i].ProjectCount = counts[technologies[i].ID]
+	}
+}
+
+// GetTechnology returns a technology by ID with its project count
+func GetTechnology(id uuid.UUID) (*models.Technology, error) {
+	var technology models.Technology
+	if err := database.GetDB().First(&technology, "id = ?", id).Error; err != nil {
+		return nil, errors.New("teknologi tidak ditemukan")
+	}
+	list := []models.Technology{technology}
+	countProjects(list)
+	return &list[0], nil
+}
+
+// TechnologyInput creates or updates a technology. Aliases are other spellings of the
+// name; they are matched regardless of case and punctuation.
+type TechnologyInput struct {
+	Name    string   `json:"name" validate:"required,min=1,max=100"`
+	Aliases []string `json:"aliases" validate:"omitempty,dive,max=100"`
+	IconURL *string  `json:"iconUrl" validate:"omitempty,url"`
+	Color   *string  `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// aliasKeys normalizes the aliases, leaving out duplicates and the key of the name itself
+func aliasKeys(aliases []string, key string) []string {
+	keys := []string{}
+	for _, alias := range aliases {
+		aliasKey := models.TechnologyKey(alias)
+		if aliasKey != "" && aliasKey != key && !slices.Contains(keys, aliasKey) {
+			keys = append(keys, aliasKey)
+		}
+	}
+	return keys
+}
+
+// checkTechnologyNames rejects a key or alias that already belongs to another technology
+func checkTechnologyNames(tx *gorm.DB, id uuid.UUID, keys []string) error {
+	for _, key := range keys {
+		var count int64
+		tx.Model(&models.Technology{}).
+			Where("id <> ? AND (key = ? OR slug = ? OR ? = ANY(aliases))", id, key, models.TechnologySlug(key), key).
+			Count(&count)
+		if count > 0 {
+			return fmt.Errorf("nama atau alias %q sudah dipakai teknologi lain", key)
+		}
+	}
+	return nil
+}
+
+// CreateTechnology adds a canonical technology
+func CreateTechnology(input *TechnologyInput) (*models.Technology, error) {
+	db := database.GetDB()
+
+	name := strings.TrimSpace(input.Name)
+	key := models.TechnologyKey(name)
+	if key == "" {
+		return nil, errors.New("nama teknologi harus mengandung huruf atau angka")
+	}
+
+	technology := models.Technology{
+		Name:    name,
+		Key:     key,
+		Slug:    models.TechnologySlug(key),
+		Aliases: aliasKeys(input.Aliases, key),
+		IconURL: input.IconURL,
+		Color:   input.Color,
+	}
+	if err := checkTechnologyNames(db, uuid.Nil, append([]string{key}, technology.Aliases...)); err != nil {
+		return nil, err
+	}
+
+	if err := db.Create(&technology).Error; err != nil {
+		return nil, fmt.Errorf("gagal membuat teknologi: %w", err)
+	}
+	return &technology, nil
+}
+
+// UpdateTechnology changes a technology. A renamed technology keeps its old name as an
+// alias and the tech stacks of its projects follow the new name.
+func UpdateTechnology(technology *models.Technology, input *TechnologyInput) error {
+	name := strings.TrimSpace(input.Name)
+	key := models.TechnologyKey(name)
+	if key == "" {
+		return errors.New("nama teknologi harus mengandung huruf atau angka")
+	}
+
+	aliases := input.Aliases
+	if key != technology.Key {
+		aliases = append(aliases, technology.Key)
+	}
+
+	oldName := technology.Name
+	technology.Name = name
+	technology.Key = key
+	technology.Slug = models.TechnologySlug(key)
+	technology.Aliases = aliasKeys(aliases, key)
+	technology.IconURL = input.IconURL
+	technology.Color = input.Color
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := checkTechnologyNames(tx, technology.ID, append([]string{key}, technology.Aliases...)); err != nil {
+			return err
+		}
+		if err := tx.Save(technology).Error; err != nil {
+			return err
+		}
+		if oldName == name {
+			return nil
+		}
+		return tx.Exec(
+			"UPDATE projects SET tech_stack = array_replace(tech_stack, ?, ?) WHERE ? = ANY(tech_stack)",
+			oldName, name, oldName,
+		).Error
+	})
+}
+
+// MergeTechnologies folds a duplicate into the target technology. Projects using the
+// duplicate move to the target, and its name and aliases become aliases of the target.
+func MergeTechnologies(source, target *models.Technology) error {
+	if source.ID == target.ID {
+		return errors.New("teknologi tidak dapat digabung dengan dirinya sendiri")
+	}
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			`INSERT INTO project_technologies (project_id, technology_id)
+			SELECT project_id, ? FROM project_technologies WHERE technology_id = ?
+			ON CONFLICT DO NOTHING`,
+			target.ID, source.ID,
+		).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM project_technologies WHERE technology_id = ?", source.ID).Error; err != nil {
+			return err
+		}
+
+		// Projects listing both keep the target once
+		if err := tx.Exec(
+			"UPDATE projects SET tech_stack = array_remove(tech_stack, ?) WHERE ? = ANY(tech_stack) AND ? = ANY(tech_stack)",
+			source.Name, source.Name, target.Name,
+		).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(
+			"UPDATE projects SET tech_stack = array_replace(tech_stack, ?, ?) WHERE ? = ANY(tech_stack)",
+			source.Name, target.Name, source.Name,
+		).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(source).Error; err != nil {
+			return err
+		}
+
+		target.Aliases = aliasKeys(append(append([]string{}, target.Aliases...), append([]string{source.Key}, source.Aliases...)...), target.Key)
+		if target.IconURL == nil {
+			target.IconURL = source.IconURL
+		}
+		if target.Color == nil {
+			target.Color = source.Color
+		}
+		return tx.Save(target).Error
+	})
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/campus-project-hub/api/internal/database"
+	"github.com/campus-project-hub/api/internal/models"
+	"github.com/campus-project-hub/api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	twoFactorIssuer        = "Campus Project Hub"
+	twoFactorChallengeTTL  = 5 * time.Minute
+	recoveryCodeCount      = 10
+	recoveryCodeByteLength = 5
+)
+
+// TwoFactorSetup is returned when a user starts enrolling an authenticator app
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// TwoFactorCodeInput carries a TOTP code or a recovery code
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorLoginInput completes a login that requires a second factor
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// IsTwoFactorRequired reports whether the role must have 2FA enabled
+func IsTwoFactorRequired(role models.UserRole) bool {
+	cfg := config.GetConfig()
+	for _, required := range cfg.Auth.TwoFactorRequiredRoles {
+		if required == string(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupTwoFactor generates a new pending TOTP secret for the user
+func SetupTwoFactor(userID uuid.UUID) (*TwoFactorSetup, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("autentikasi dua faktor sudah aktif")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat secret: %w", err)
+	}
+
+	db := database.GetDB()
+	if err := db.Model(user).Update("totp_secret", secret).Error; err != nil {
+		return nil, fmt.Errorf("gagal menyimpan secret: %w", err)
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, user.Email, twoFactorIssuer),
+	}, nil
+}
+
+// EnableTwoFactor confirms the pending secret with a code and returns fresh recovery codes
+func EnableTwoFactor(userID uuid.UUID, code string) ([]string, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("autentikasi dua faktor sudah aktif")
+	}
+	if user.TOTPSecret == nil {
+		return nil, errors.New("mulai pengaturan autentikasi dua faktor terlebih dahulu")
+	}
+
+	step, ok := utils.ValidateTOTP(*user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, errors.New("kode autentikasi tidak valid")
+	}
+
+	var codes []string
+	db := database.GetDB()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"totp_enabled_at": time.Now(),
+			"totp_last_step":  step,
+		}).Error; err != nil {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengaktifkan autentikasi dua faktor: %w", err)
+	}
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns off 2FA after verifying a code. Roles that require 2FA cannot disable it.
+func DisableTwoFactor(userID uuid.UUID, code string) error {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return errors.New("autentikasi dua faktor belum aktif")
+	}
+	if IsTwoFactorRequired(user.Role) {
+		return errors.New("autentikasi dua faktor wajib untuk role Anda")
+	}
+	if !verifySecondFactor(user, code) {
+		return errors.New("kode autentikasi tidak valid")
+	}
+
+	db := database.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"totp_secret":     nil,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes invalidates the old recovery codes after verifying a code
+func RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return nil, errors.New("autentikasi dua faktor belum aktif")
+	}
+	if !verifySecondFactor(user, code) {
+		return nil, errors.New("kode autentikasi tidak valid")
+	}
+
+	var codes []string
+	db := database.GetDB()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat kode pemulihan: %w", err)
+	}
+
+	return codes, nil
+}
+
+// startTwoFactorChallenge issues a short-lived token proving the password step succeeded
+func startTwoFactorChallenge(user *models.User) (*AuthResult, error) {
+	token, err := utils.GenerateActionToken(user.ID, user.Email, utils.PurposeTwoFactorChallenge, twoFactorChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat token: %w", err)
+	}
+
+	return &AuthResult{
+		User:              user,
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// VerifyTwoFactorLogin completes a login with the challenge token and a TOTP or recovery code
+func VerifyTwoFactorLogin(input *TwoFactorLoginInput, client ClientInfo) (*AuthResult, error) {
+	claims, err := utils.ValidateActionToken(input.ChallengeToken, utils.PurposeTwoFactorChallenge)
+	if err != nil {
+		return nil, errors.New("sesi login sudah kedaluwarsa, silakan login kembali")
+	}
+
+	user, err := GetUserByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status == models.StatusBlocked {
+		return nil, errors.New("akun Anda telah diblokir")
+	}
+
+	// Wrong codes count against the same lockout as wrong passwords
+	if err := checkLoginAllowed(user.Email, client); err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() || !verifySecondFactor(user, input.Code) {
+		recordLoginFailure(user.Email, client)
+		return nil, errors.New("kode autentikasi tidak valid")
+	}
+	recordLoginSuccess(user.Email)
+
+	tokenPair, err := CreateSession(user, client)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat token: %w", err)
+	}
+
+	return &AuthResult{
+		User:      user,
+		TokenPair: tokenPair,
+	}, nil
+}
+
+// verifySecondFactor accepts a TOTP code that was not used before, or an unused recovery code
+func verifySecondFactor(user *models.User, code string) bool {
+	db := database.GetDB()
+	code = strings.TrimSpace(code)
+
+	if step, ok := utils.ValidateTOTP(*user.TOTPSecret, code, time.Now()); ok {
+		// Advance the last step atomically so a code cannot be replayed
+		result := db.Model(&models.User{}).
+			Where("id = ? AND totp_last_step < ?", user.ID, step).
+			Update("totp_last_step", step)
+		return result.Error == nil && result.RowsAffected == 1
+	}
+
+	result := db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	records := make([]models.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, recoveryCodeByteLength)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		encoded := hex.EncodeToString(raw)
+		code := encoded[:5] + "-" + encoded[5:]
+		codes[i] = code
+		records[i] = models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		}
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	return utils.HashToken(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", "")))
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ActionPurpose restricts what a signed action token can be used for
+type ActionPurpose string
+
+const (
+	PurposeVerifyEmail        ActionPurpose = "verify_email"
+	PurposeTwoFactorChallenge ActionPurpose = "two_factor_challenge"
+	PurposeDownload           ActionPurpose = "download"
+)
+
+// ActionClaims are carried by short-lived tokens sent in emails and signed download URLs.
+// Download tokens name the file in the subject and their single-use record in the jti.
+type ActionClaims struct {
+	UserID  uuid.UUID     `json:"userId"`
+	Email   string        `json:"email"`
+	Purpose ActionPurpose `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken signs a token that can only be used for the given purpose
+func GenerateActionToken(userID uuid.UUID, email string, purpose ActionPurpose, ttl time.Duration) (string, error) {
+	cfg := config.GetConfig()
+
+	claims := &ActionClaims{
+		UserID:  userID,
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    cfg.App.Name,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWT.Secret))
+}
+
+// GenerateDownloadToken signs a token that lets the user download one file once before it
+// expires. tokenID is carried in the jti claim and names the record that tracks its use.
+func GenerateDownloadToken(userID, fileID, tokenID uuid.UUID, ttl time.Duration) (string, error) {
+	cfg := config.GetConfig()
+
+	claims := &ActionClaims{
+		UserID:  userID,
+		Purpose: PurposeDownload,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID.String(),
+			Subject:   fileID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    cfg.App.Name,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWT.Secret))
+}
+
+// ValidateActionToken verifies the signature, expiry and purpose of an action token
+func ValidateActionToken(tokenString string, purpose ActionPurpose) (*ActionClaims, error) {
+	cfg := config.GetConfig()
+
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(cfg.JWT.Secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid || claims.Purpose != purpose {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
@@ -21,6 +21,9 @@ type Claims struct {
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
 	TokenType TokenType `json:"tokenType"`
+	Nonce     string    `json:"nonce,omitempty"`
+	// ImpersonatedBy is the admin acting as the user, only set on impersonation tokens
+	ImpersonatedBy *uuid.UUID `json:"impersonatedBy,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -30,7 +33,9 @@ type TokenPair struct {
 	ExpiresIn    int64  `json:"expiresIn"`
 }
 
-func GenerateTokenPair(userID uuid.UUID, email, role string) (*TokenPair, error) {
+// GenerateTokenPair issues an access and refresh token bound to a session.
+// The session ID is carried in the jti claim of both tokens.
+func GenerateTokenPair(userID uuid.UUID, email, role string, sessionID uuid.UUID) (*TokenPair, error) {
 	cfg := config.GetConfig()
 
 	// Access Token
@@ -41,32 +46,38 @@ func GenerateTokenPair(userID uuid.UUID, email, role string) (*TokenPair, error)
 		Role:      role,
 		TokenType: AccessToken,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID.String(),
 			ExpiresAt: jwt.NewNumericDate(accessExpiry),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    cfg.App.Name,
 		},
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(cfg.JWT.Secret))
+	accessTokenString, err := signWithActiveKey(accessClaims)
 	if err != nil {
 		return nil, err
 	}
 
 	// Refresh Token
+	// The nonce makes every rotated refresh token unique, even within the same second
+	nonce, err := GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
 	refreshExpiry := time.Now().Add(time.Duration(cfg.JWT.RefreshExpiryHours) * time.Hour)
 	refreshClaims := &Claims{
 		UserID:    userID,
 		Email:     email,
 		Role:      role,
 		TokenType: RefreshToken,
+		Nonce:     nonce,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID.String(),
 			ExpiresAt: jwt.NewNumericDate(refreshExpiry),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    cfg.App.Name,
 		},
 	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(cfg.JWT.Secret))
+	refreshTokenString, err := signWithActiveKey(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -78,15 +89,32 @@ func GenerateTokenPair(userID uuid.UUID, email, role string) (*TokenPair, error)
 	}, nil
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
+// GenerateImpersonationToken issues a short-lived access token that lets an admin act as
+// the user. There is no refresh token; the admin starts a new impersonation when it expires.
+func GenerateImpersonationToken(userID uuid.UUID, email, role string, sessionID, impersonatorID uuid.UUID, ttl time.Duration) (string, error) {
 	cfg := config.GetConfig()
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(cfg.JWT.Secret), nil
-	})
+	claims := &Claims{
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		TokenType:      AccessToken,
+		ImpersonatedBy: &impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    cfg.App.Name,
+		},
+	}
+	return signWithActiveKey(claims)
+}
+
+// ValidateToken verifies a token against the key named by its kid header
+func ValidateToken(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+	)
 
 	if err != nil {
 		return nil, err
@@ -111,3 +139,21 @@ func ValidateRefreshToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+func ValidateAccessToken(tokenString string) (*Claims, error) {
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != AccessToken {
+		return nil, errors.New("invalid token type")
+	}
+
+	return claims, nil
+}
+
+// SessionID returns the session the token was issued for
+func (c *Claims) SessionID() (uuid.UUID, error) {
+	return uuid.Parse(c.ID)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -76,6 +77,29 @@ func NotFound(c *gin.Context, message string) {
 	Error(c, http.StatusNotFound, message)
 }
 
+// Conflict sends a 409 response
+func Conflict(c *gin.Context, message string) {
+	Error(c, http.StatusConflict, message)
+}
+
+// TooManyRequests sends a 429 response with a Retry-After header
+func TooManyRequests(c *gin.Context, retryAfterSeconds int, message string) {
+	c.Header("Retry-After", strconv.Itoa(retryAfterSeconds))
+	Error(c, http.StatusTooManyRequests, message)
+}
+
+// MovedPermanently sends a 301 to the canonical location, keeping the query string
+func MovedPermanently(c *gin.Context, location string) {
+	if query := c.Request.URL.RawQuery; query != "" {
+		location += "?" + query
+	}
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, APIResponse{
+		Success: true,
+		Data:    gin.H{"location": location},
+	})
+}
+
 // InternalServerError sends a 500 response
 func InternalServerError(c *gin.Context, message string) {
 	Error(c, http.StatusInternalServerError, message)
@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is an asymmetric key identified by the kid header of the tokens it signs
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeySet holds the key that signs new tokens and every key that may verify them
+type KeySet struct {
+	Active *SigningKey
+	Keys   map[string]*SigningKey
+}
+
+// JSONWebKey is the public part of a signing key as published in the JWKS
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+var (
+	keySet     *KeySet
+	keySetErr  error
+	keySetOnce sync.Once
+)
+
+// LoadSigningKeys reads the configured keys. It is called at startup so a broken key
+// configuration stops the server instead of failing the first login.
+func LoadSigningKeys() error {
+	_, err := getKeySet()
+	return err
+}
+
+func getKeySet() (*KeySet, error) {
+	keySetOnce.Do(func() {
+		keySet, keySetErr = buildKeySet(config.GetConfig())
+	})
+	return keySet, keySetErr
+}
+
+func buildKeySet(cfg *config.Config) (*KeySet, error) {
+	set := &KeySet{Keys: make(map[string]*SigningKey)}
+
+	if len(cfg.JWT.Keys) == 0 {
+		if cfg.App.IsProduction() {
+			return nil, errors.New("no jwt signing keys configured")
+		}
+		log.Println("Warning: no jwt.keys configured, using a temporary signing key. Tokens become invalid on restart.")
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key := &SigningKey{ID: "dev", Method: jwt.SigningMethodEdDSA, Private: private, Public: private.Public()}
+		set.Keys[key.ID] = key
+		set.Active = key
+		return set, nil
+	}
+
+	for _, keyConfig := range cfg.JWT.Keys {
+		if keyConfig.ID == "" {
+			return nil, errors.New("every jwt key needs an id")
+		}
+		if _, exists := set.Keys[keyConfig.ID]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id %q", keyConfig.ID)
+		}
+
+		key, err := loadSigningKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", keyConfig.ID, err)
+		}
+		set.Keys[key.ID] = key
+	}
+
+	// Default to the first key that can sign
+	activeID := cfg.JWT.ActiveKeyID
+	if activeID == "" {
+		for _, keyConfig := range cfg.JWT.Keys {
+			if keyConfig.PrivateKeyFile != "" {
+				activeID = keyConfig.ID
+				break
+			}
+		}
+	}
+
+	active, ok := set.Keys[activeID]
+	if !ok || active.Private == nil {
+		return nil, fmt.Errorf("active jwt key %q not found or has no private key", activeID)
+	}
+	set.Active = active
+
+	return set, nil
+}
+
+func loadSigningKey(keyConfig config.JWTKeyConfig) (*SigningKey, error) {
+	key := &SigningKey{ID: keyConfig.ID}
+
+	switch {
+	case keyConfig.PrivateKeyFile != "":
+		block, err := readPEM(keyConfig.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			// Fall back to the "BEGIN RSA PRIVATE KEY" format written by older openssl versions
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid private key: %w", err)
+			}
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key")
+		}
+		key.Private = signer
+		key.Public = signer.Public()
+	case keyConfig.PublicKeyFile != "":
+		block, err := readPEM(keyConfig.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		key.Public = parsed
+	default:
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < 2048 {
+			return nil, errors.New("rsa keys must be at least 2048 bits")
+		}
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
+}
+
+// signWithActiveKey signs the claims with the active key and sets the kid header
+func signWithActiveKey(claims jwt.Claims) (string, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(set.Active.Method, claims)
+	token.Header["kid"] = set.Active.ID
+	return token.SignedString(set.Active.Private)
+}
+
+// verificationKey picks the key named by the kid header and checks that the algorithm matches it
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := set.Keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.Public, nil
+}
+
+// JWKS returns the public keys that verify access and refresh tokens
+func JWKS() ([]JSONWebKey, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]JSONWebKey, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		jwk := JSONWebKey{Kid: key.ID, Alg: key.Method.Alg(), Use: "sig"}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		keys = append(keys, jwk)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Kid < keys[j].Kid })
+	return keys, nil
+}
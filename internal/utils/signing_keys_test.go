@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/campus-project-hub/api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// writeKeyFiles writes the key as PKCS#8 and PKIX PEM files and returns their paths
+func writeKeyFiles(t *testing.T, name string, private crypto.Signer) (string, string) {
+	t.Helper()
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+// useKeySet replaces the loaded signing keys for the duration of a test
+func useKeySet(t *testing.T, cfg *config.Config) {
+	t.Helper()
+
+	set, err := buildKeySet(cfg)
+	if err != nil {
+		t.Fatalf("buildKeySet() error = %v", err)
+	}
+
+	keySetOnce.Do(func() {})
+	previousSet, previousErr := keySet, keySetErr
+	keySet, keySetErr = set, nil
+	t.Cleanup(func() { keySet, keySetErr = previousSet, previousErr })
+}
+
+func testAccessClaims() *Claims {
+	return &Claims{
+		UserID:    uuid.New(),
+		Email:     "budi@kampus.ac.id",
+		Role:      "user",
+		TokenType: AccessToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldPrivate, oldPublic := writeKeyFiles(t, "old", rsaKey)
+	newPrivate, _ := writeKeyFiles(t, "new", edKey)
+
+	// Before the rotation the RSA key signs
+	useKeySet(t, &config.Config{JWT: config.JWTConfig{Keys: []config.JWTKeyConfig{
+		{ID: "2025-01", PrivateKeyFile: oldPrivate},
+	}}})
+	oldToken, err := signWithActiveKey(testAccessClaims())
+	if err != nil {
+		t.Fatalf("sign with old key: %v", err)
+	}
+
+	// After the rotation the Ed25519 key signs and the RSA key only verifies
+	useKeySet(t, &config.Config{JWT: config.JWTConfig{
+		ActiveKeyID: "2026-01",
+		Keys: []config.JWTKeyConfig{
+			{ID: "2026-01", PrivateKeyFile: newPrivate},
+			{ID: "2025-01", PublicKeyFile: oldPublic},
+		},
+	}})
+
+	if _, err := ValidateAccessToken(oldToken); err != nil {
+		t.Errorf("token signed before the rotation rejected: %v", err)
+	}
+
+	newToken, err := signWithActiveKey(testAccessClaims())
+	if err != nil {
+		t.Fatalf("sign with new key: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := parsed.Header["kid"]; kid != "2026-01" {
+		t.Errorf("new token kid = %v, want 2026-01", kid)
+	}
+	if _, err := ValidateAccessToken(newToken); err != nil {
+		t.Errorf("token signed with the active key rejected: %v", err)
+	}
+
+	keys, err := JWKS()
+	if err != nil {
+		t.Fatalf("JWKS() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0].Kid != "2025-01" || keys[0].Kty != "RSA" || keys[1].Kid != "2026-01" || keys[1].Kty != "OKP" {
+		t.Errorf("JWKS() = %+v, want the RSA and Ed25519 keys", keys)
+	}
+	for _, key := range keys {
+		if key.Use != "sig" {
+			t.Errorf("JWKS key %s use = %q, want sig", key.Kid, key.Use)
+		}
+	}
+
+	// Retiring the old key invalidates its tokens
+	useKeySet(t, &config.Config{JWT: config.JWTConfig{Keys: []config.JWTKeyConfig{
+		{ID: "2026-01", PrivateKeyFile: newPrivate},
+	}}})
+	if _, err := ValidateAccessToken(oldToken); err == nil || !strings.Contains(err.Error(), "unknown signing key") {
+		t.Errorf("token of a retired key: error = %v, want unknown signing key", err)
+	}
+}
+
+func TestValidateTokenRejectsForgedKeyHeaders(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, attackerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivate, _ := writeKeyFiles(t, "rsa", rsaKey)
+	edPrivate, _ := writeKeyFiles(t, "ed", edKey)
+
+	useKeySet(t, &config.Config{JWT: config.JWTConfig{
+		ActiveKeyID: "ed",
+		Keys: []config.JWTKeyConfig{
+			{ID: "ed", PrivateKeyFile: edPrivate},
+			{ID: "rsa", PrivateKeyFile: rsaPrivate},
+		},
+	}})
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, testAccessClaims())
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown kid", sign(jwt.SigningMethodEdDSA, "other", attackerKey)},
+		{"missing kid", sign(jwt.SigningMethodEdDSA, "", edKey)},
+		{"foreign key under a known kid", sign(jwt.SigningMethodEdDSA, "ed", attackerKey)},
+		{"algorithm of another key", sign(jwt.SigningMethodEdDSA, "rsa", edKey)},
+		{"symmetric algorithm", sign(jwt.SigningMethodHS256, "ed", []byte("secret"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateToken(tt.token); err == nil {
+				t.Error("ValidateToken() accepted the token")
+			}
+		})
+	}
+}
+
+func TestBuildKeySetErrors(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, public := writeKeyFiles(t, "key", edKey)
+
+	tests := []struct {
+		name string
+		keys []config.JWTKeyConfig
+		want string
+	}{
+		{"duplicate id", []config.JWTKeyConfig{{ID: "a", PrivateKeyFile: private}, {ID: "a", PublicKeyFile: public}}, "duplicate"},
+		{"missing id", []config.JWTKeyConfig{{PrivateKeyFile: private}}, "needs an id"},
+		{"no signing key", []config.JWTKeyConfig{{ID: "a", PublicKeyFile: public}}, "no private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildKeySet(&config.Config{JWT: config.JWTConfig{Keys: tt.keys}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("buildKeySet() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slugify turns text into a lowercase URL segment of ASCII letters, digits and single
+// hyphens, at most maxLen characters long. Accents are dropped ("Café" becomes "cafe").
+func Slugify(s string, maxLen int) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent left over from the decomposition
+			continue
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		default:
+			pendingHyphen = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxLen {
+		slug = strings.TrimRight(slug[:maxLen], "-")
+	}
+	return slug
+}
+
+// IsValidSlug reports whether s is already in the form Slugify produces
+func IsValidSlug(s string) bool {
+	return slugPattern.MatchString(s)
+}
@@ -0,0 +1,40 @@
+// Package worker runs periodic background jobs inside the API process
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a named task that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Start runs every job in its own goroutine until the context is cancelled.
+// Each job runs once right away and then on its interval.
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(); err != nil {
+			log.Printf("Worker %s failed: %v", job.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
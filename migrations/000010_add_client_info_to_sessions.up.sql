@@ -0,0 +1,2 @@
+ALTER TABLE sessions ADD COLUMN user_agent TEXT;
+ALTER TABLE sessions ADD COLUMN ip_address VARCHAR(45);
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Accounts created before email verification existed are trusted as verified
+UPDATE users SET email_verified_at = created_at;
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN oauth_provider VARCHAR(20);
+ALTER TABLE users ADD COLUMN oauth_id VARCHAR(255);
+CREATE INDEX idx_users_oauth ON users(oauth_provider, oauth_id);
+
+-- Keep the oldest identity of each user
+UPDATE users u
+SET oauth_provider = i.provider, oauth_id = i.provider_user_id
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, provider, provider_user_id
+    FROM user_identities
+    ORDER BY user_id, created_at
+) i
+WHERE u.id = i.user_id;
+
+DROP TABLE IF EXISTS user_identities;
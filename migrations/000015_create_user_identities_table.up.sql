@@ -0,0 +1,22 @@
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_user_id VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_user_identities_provider_subject ON user_identities(provider, provider_user_id);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- Move the single provider link stored on users into the new table
+INSERT INTO user_identities (user_id, provider, provider_user_id, email, created_at, last_used_at)
+SELECT id, oauth_provider, oauth_id, email, created_at, updated_at
+FROM users
+WHERE oauth_provider IS NOT NULL AND oauth_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_oauth;
+ALTER TABLE users DROP COLUMN oauth_provider;
+ALTER TABLE users DROP COLUMN oauth_id;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS student_verifications;
+
+DROP INDEX IF EXISTS idx_users_student_verified;
+DROP INDEX IF EXISTS idx_users_campus_email;
+ALTER TABLE users DROP COLUMN IF EXISTS student_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS verified_university_id;
+ALTER TABLE users DROP COLUMN IF EXISTS campus_email;
+
+DROP TABLE IF EXISTS universities;
@@ -0,0 +1,27 @@
+CREATE TABLE universities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) UNIQUE NOT NULL,
+    domains TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_universities_domains ON universities USING GIN(domains);
+
+ALTER TABLE users ADD COLUMN campus_email VARCHAR(255);
+ALTER TABLE users ADD COLUMN verified_university_id UUID REFERENCES universities(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN student_verified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX idx_users_campus_email ON users(LOWER(campus_email)) WHERE campus_email IS NOT NULL;
+CREATE INDEX idx_users_student_verified ON users(student_verified_at) WHERE student_verified_at IS NOT NULL;
+
+CREATE TABLE student_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID UNIQUE NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    university_id UUID NOT NULL REFERENCES universities(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_logs;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS impersonated_by;
@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS hide_from_leaderboard;
+ALTER TABLE users DROP COLUMN IF EXISTS show_university;
+ALTER TABLE users DROP COLUMN IF EXISTS show_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS show_email;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN show_email BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN show_phone BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN show_university BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE users ADD COLUMN hide_from_leaderboard BOOLEAN NOT NULL DEFAULT false;
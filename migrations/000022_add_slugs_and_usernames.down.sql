@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS slug_redirects;
+DROP INDEX IF EXISTS idx_projects_user_slug;
+DROP INDEX IF EXISTS idx_users_username;
+ALTER TABLE projects DROP COLUMN IF EXISTS slug;
+ALTER TABLE users DROP COLUMN IF EXISTS username;
@@ -0,0 +1,54 @@
+-- unaccent keeps accented letters the way utils.Slugify does ("Café" becomes "cafe")
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+ALTER TABLE users ADD COLUMN username VARCHAR(30);
+ALTER TABLE projects ADD COLUMN slug VARCHAR(120);
+
+-- Derive usernames from names. Duplicates get part of the ID so the result stays unique.
+WITH derived AS (
+    SELECT id, created_at,
+           COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(REGEXP_REPLACE(LOWER(unaccent(name)), '[^a-z0-9]+', '-', 'g'), 21)), ''), 'user') AS base
+    FROM users
+), ranked AS (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY created_at, id) AS n
+    FROM derived
+)
+UPDATE users SET username = CASE
+        WHEN ranked.n = 1 AND LENGTH(ranked.base) >= 3 THEN ranked.base
+        ELSE ranked.base || '-' || LEFT(REPLACE(users.id::text, '-', ''), 8)
+    END
+FROM ranked
+WHERE users.id = ranked.id;
+
+-- Same for project slugs, scoped by author
+WITH derived AS (
+    SELECT id, user_id, created_at,
+           COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(REGEXP_REPLACE(LOWER(unaccent(title)), '[^a-z0-9]+', '-', 'g'), 110)), ''), 'project') AS base
+    FROM projects
+), ranked AS (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY user_id, base ORDER BY created_at, id) AS n
+    FROM derived
+)
+UPDATE projects SET slug = CASE
+        WHEN ranked.n = 1 THEN ranked.base
+        ELSE ranked.base || '-' || LEFT(REPLACE(projects.id::text, '-', ''), 8)
+    END
+FROM ranked
+WHERE projects.id = ranked.id;
+
+ALTER TABLE users ALTER COLUMN username SET NOT NULL;
+ALTER TABLE projects ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_users_username ON users(username);
+CREATE UNIQUE INDEX idx_projects_user_slug ON projects(user_id, slug);
+
+CREATE TABLE IF NOT EXISTS slug_redirects (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_type VARCHAR(20) NOT NULL,
+    scope_id UUID NOT NULL,
+    slug VARCHAR(120) NOT NULL,
+    resource_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_slug_redirects_slug ON slug_redirects(resource_type, scope_id, slug);
+CREATE INDEX idx_slug_redirects_resource_id ON slug_redirects(resource_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_projects_scheduled;
+
+-- Projects that never went live fall back to drafts
+UPDATE projects SET status = 'draft' WHERE status IN ('pending_review', 'rejected', 'scheduled');
+
+ALTER TABLE projects DROP COLUMN IF EXISTS review_reason;
+ALTER TABLE projects DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE projects DROP COLUMN IF EXISTS published_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,10 @@
+ALTER TABLE projects ADD COLUMN publish_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE projects ADD COLUMN published_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE projects ADD COLUMN reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE projects ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE projects ADD COLUMN review_reason TEXT;
+
+UPDATE projects SET published_at = created_at WHERE status = 'published';
+
+-- The publishing worker looks for scheduled projects that are due
+CREATE INDEX idx_projects_scheduled ON projects(publish_at) WHERE status = 'scheduled';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transaction_shares;
+DROP TABLE IF EXISTS project_collaborators;
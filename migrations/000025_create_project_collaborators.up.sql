@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS project_collaborators (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    revenue_share INTEGER NOT NULL DEFAULT 0 CHECK (revenue_share BETWEEN 0 AND 100),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_project_collaborators_member ON project_collaborators(project_id, user_id);
+CREATE INDEX idx_project_collaborators_user_id ON project_collaborators(user_id);
+
+-- Every existing project is owned by its author, who keeps all revenue
+INSERT INTO project_collaborators (project_id, user_id, role, status, revenue_share, accepted_at, created_at)
+SELECT id, user_id, 'owner', 'accepted', 100, created_at, created_at FROM projects;
+
+CREATE TABLE IF NOT EXISTS transaction_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    percent INTEGER NOT NULL,
+    amount INTEGER NOT NULL
+);
+
+CREATE INDEX idx_transaction_shares_transaction_id ON transaction_shares(transaction_id);
+CREATE INDEX idx_transaction_shares_user_id ON transaction_shares(user_id);
+
+-- Past sales went entirely to the seller
+INSERT INTO transaction_shares (transaction_id, user_id, percent, amount)
+SELECT id, seller_id, 100, amount FROM transactions;
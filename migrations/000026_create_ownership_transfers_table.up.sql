@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS ownership_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_type VARCHAR(20) NOT NULL,
+    resource_id UUID NOT NULL,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_user_id UUID NOT NULL REFERENCES users(id),
+    requested_by UUID NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    message TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_ownership_transfers_resource ON ownership_transfers(resource_type, resource_id);
+CREATE INDEX idx_ownership_transfers_from_user_id ON ownership_transfers(from_user_id);
+CREATE INDEX idx_ownership_transfers_to_user_id ON ownership_transfers(to_user_id);
+
+-- A resource has at most one open transfer request
+CREATE UNIQUE INDEX idx_ownership_transfers_pending ON ownership_transfers(resource_type, resource_id) WHERE status = 'pending';
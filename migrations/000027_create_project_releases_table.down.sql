@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS release_assets;
+DROP TABLE IF EXISTS project_releases;
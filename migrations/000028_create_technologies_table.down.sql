@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_technologies;
+DROP TABLE IF EXISTS technologies;
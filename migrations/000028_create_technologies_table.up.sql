@@ -0,0 +1,138 @@
+CREATE TABLE IF NOT EXISTS technologies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    icon_url TEXT,
+    color VARCHAR(20),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_technologies_key ON technologies(key);
+CREATE UNIQUE INDEX idx_technologies_slug ON technologies(slug);
+CREATE INDEX idx_technologies_aliases ON technologies USING GIN(aliases);
+
+CREATE TABLE IF NOT EXISTS project_technologies (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    technology_id UUID NOT NULL REFERENCES technologies(id) ON DELETE CASCADE,
+    PRIMARY KEY (project_id, technology_id)
+);
+
+CREATE INDEX idx_project_technologies_technology_id ON project_technologies(technology_id);
+
+-- Same normalization as models.TechnologyKey
+CREATE FUNCTION pg_temp.technology_key(name TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(name, '[^a-zA-Z0-9+#]', '', 'g'))
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Same lookup as services.findTechnology, including the fallback without a trailing "js"
+CREATE FUNCTION pg_temp.technology_for(name TEXT) RETURNS UUID AS $$
+    SELECT t.id
+    FROM technologies t,
+        (VALUES (1, pg_temp.technology_key(name)),
+                (2, regexp_replace(pg_temp.technology_key(name), '(.)js$', '\1'))) AS c(rank, k)
+    WHERE t.key = c.k OR t.slug = replace(replace(c.k, '+', 'p'), '#', 'sharp') OR c.k = ANY(t.aliases)
+    ORDER BY c.rank
+    LIMIT 1
+$$ LANGUAGE SQL STABLE;
+
+-- Common technologies with the spellings students use for them
+INSERT INTO technologies (name, key, slug, aliases, color) VALUES
+    ('React', 'react', 'react', '{reactjs}', '#61DAFB'),
+    ('React Native', 'reactnative', 'reactnative', '{rn}', '#61DAFB'),
+    ('Vue.js', 'vuejs', 'vuejs', '{vue}', '#4FC08D'),
+    ('Angular', 'angular', 'angular', '{angularjs}', '#DD0031'),
+    ('Svelte', 'svelte', 'svelte', '{sveltejs}', '#FF3E00'),
+    ('Next.js', 'nextjs', 'nextjs', '{next}', '#000000'),
+    ('Nuxt', 'nuxt', 'nuxt', '{nuxtjs}', '#00DC82'),
+    ('Node.js', 'nodejs', 'nodejs', '{node}', '#339933'),
+    ('Express', 'express', 'express', '{expressjs}', '#000000'),
+    ('NestJS', 'nestjs', 'nestjs', '{nest}', '#E0234E'),
+    ('JavaScript', 'javascript', 'javascript', '{js,es6}', '#F7DF1E'),
+    ('TypeScript', 'typescript', 'typescript', '{ts}', '#3178C6'),
+    ('HTML', 'html', 'html', '{html5}', '#E34F26'),
+    ('CSS', 'css', 'css', '{css3}', '#1572B6'),
+    ('Tailwind CSS', 'tailwindcss', 'tailwindcss', '{tailwind}', '#06B6D4'),
+    ('Bootstrap', 'bootstrap', 'bootstrap', '{}', '#7952B3'),
+    ('PHP', 'php', 'php', '{}', '#777BB4'),
+    ('Laravel', 'laravel', 'laravel', '{}', '#FF2D20'),
+    ('CodeIgniter', 'codeigniter', 'codeigniter', '{ci,ci4}', '#EF4223'),
+    ('Python', 'python', 'python', '{py,python3}', '#3776AB'),
+    ('Django', 'django', 'django', '{}', '#092E20'),
+    ('Flask', 'flask', 'flask', '{}', '#000000'),
+    ('FastAPI', 'fastapi', 'fastapi', '{}', '#009688'),
+    ('Go', 'go', 'go', '{golang}', '#00ADD8'),
+    ('Java', 'java', 'java', '{}', '#007396'),
+    ('Spring Boot', 'springboot', 'springboot', '{spring}', '#6DB33F'),
+    ('Kotlin', 'kotlin', 'kotlin', '{}', '#7F52FF'),
+    ('Swift', 'swift', 'swift', '{}', '#F05138'),
+    ('Dart', 'dart', 'dart', '{}', '#0175C2'),
+    ('Flutter', 'flutter', 'flutter', '{}', '#02569B'),
+    ('C', 'c', 'c', '{}', '#A8B9CC'),
+    ('C++', 'c++', 'cpp', '{}', '#00599C'),
+    ('C#', 'c#', 'csharp', '{}', '#512BD4'),
+    ('.NET', 'net', 'net', '{dotnet,netcore,aspnet}', '#512BD4'),
+    ('Rust', 'rust', 'rust', '{}', '#000000'),
+    ('Ruby on Rails', 'rubyonrails', 'rubyonrails', '{rails,ror}', '#CC0000'),
+    ('MySQL', 'mysql', 'mysql', '{}', '#4479A1'),
+    ('PostgreSQL', 'postgresql', 'postgresql', '{postgres,psql}', '#4169E1'),
+    ('MongoDB', 'mongodb', 'mongodb', '{mongo}', '#47A248'),
+    ('SQLite', 'sqlite', 'sqlite', '{sqlite3}', '#003B57'),
+    ('Redis', 'redis', 'redis', '{}', '#DC382D'),
+    ('Firebase', 'firebase', 'firebase', '{}', '#FFCA28'),
+    ('Supabase', 'supabase', 'supabase', '{}', '#3FCF8E'),
+    ('Docker', 'docker', 'docker', '{}', '#2496ED'),
+    ('Kubernetes', 'kubernetes', 'kubernetes', '{k8s}', '#326CE5'),
+    ('GraphQL', 'graphql', 'graphql', '{}', '#E10098'),
+    ('TensorFlow', 'tensorflow', 'tensorflow', '{tf}', '#FF6F00'),
+    ('PyTorch', 'pytorch', 'pytorch', '{torch}', '#EE4C2C'),
+    ('Arduino', 'arduino', 'arduino', '{}', '#00979D'),
+    ('Figma', 'figma', 'figma', '{}', '#F24E1E'),
+    ('Unity', 'unity', 'unity', '{unity3d}', '#000000')
+ON CONFLICT DO NOTHING;
+
+-- Every other name in use becomes a technology under its most used spelling. A name
+-- ending in "js" is left out when the name without it is also new, as the lookup
+-- falls back to that one.
+WITH names AS (
+    SELECT trim(entry) AS name, pg_temp.technology_key(entry) AS key, COUNT(*) AS uses
+    FROM projects, unnest(tech_stack) AS entry
+    GROUP BY 1, 2
+), unknown AS (
+    SELECT * FROM names
+    WHERE key <> '' AND length(name) <= 100 AND pg_temp.technology_for(name) IS NULL
+)
+INSERT INTO technologies (name, key, slug)
+SELECT DISTINCT ON (u.key) u.name, u.key, replace(replace(u.key, '+', 'p'), '#', 'sharp')
+FROM unknown u
+WHERE NOT EXISTS (
+    SELECT 1 FROM unknown o
+    WHERE u.key ~ '.js$' AND o.key = regexp_replace(u.key, 'js$', '')
+)
+ORDER BY u.key, u.uses DESC, u.name
+ON CONFLICT DO NOTHING;
+
+INSERT INTO project_technologies (project_id, technology_id)
+SELECT DISTINCT p.id, pg_temp.technology_for(entry)
+FROM projects p, unnest(p.tech_stack) AS entry
+WHERE pg_temp.technology_for(entry) IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- Tech stacks list the canonical names once each, in their original order
+UPDATE projects SET tech_stack = canonical.names
+FROM (
+    SELECT id, array_agg(name ORDER BY position) AS names
+    FROM (
+        SELECT DISTINCT ON (pr.id, COALESCE(t.id::text, s.name))
+            pr.id, COALESCE(t.name, trim(s.name)) AS name, s.position
+        FROM projects pr
+        CROSS JOIN LATERAL unnest(pr.tech_stack) WITH ORDINALITY AS s(name, position)
+        LEFT JOIN technologies t ON t.id = pg_temp.technology_for(s.name)
+        WHERE pg_temp.technology_key(s.name) <> ''
+        ORDER BY pr.id, COALESCE(t.id::text, s.name), s.position
+    ) entries
+    GROUP BY id
+) canonical
+WHERE projects.id = canonical.id;
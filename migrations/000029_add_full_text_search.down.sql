@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_users_search_vector;
+DROP INDEX IF EXISTS idx_articles_search_vector;
+DROP INDEX IF EXISTS idx_projects_search_vector;
+
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS search_array_text(TEXT[]);
+DROP FUNCTION IF EXISTS search_document(TEXT, "char");
@@ -0,0 +1,33 @@
+-- Documents are indexed in Indonesian and English so that either search language
+-- (search.language) finds stemmed words without rebuilding the index
+CREATE OR REPLACE FUNCTION search_document(document TEXT, weight "char") RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('pg_catalog.indonesian', coalesce(document, '')), weight)
+        || setweight(to_tsvector('pg_catalog.english', coalesce(document, '')), weight)
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- array_to_string is only stable, which generated columns do not accept
+CREATE OR REPLACE FUNCTION search_array_text(items TEXT[]) RETURNS TEXT AS $$
+    SELECT coalesce(array_to_string(items, ' '), '')
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Technology and author names are matched as written, without stemming
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    search_document(title, 'A')
+    || search_document(description, 'B')
+    || setweight(to_tsvector('pg_catalog.simple', search_array_text(tech_stack)), 'C')
+) STORED;
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    search_document(title, 'A')
+    || search_document(excerpt, 'B')
+    || search_document(content, 'B')
+    || setweight(to_tsvector('pg_catalog.simple', coalesce(category, '')), 'C')
+) STORED;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('pg_catalog.simple', coalesce(name, '') || ' ' || coalesce(username, ''))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN(search_vector);
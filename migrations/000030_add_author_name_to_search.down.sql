@@ -0,0 +1,35 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('pg_catalog.simple', coalesce(name, '') || ' ' || coalesce(username, ''))
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN(search_vector);
+
+DROP INDEX IF EXISTS idx_projects_search_vector;
+DROP INDEX IF EXISTS idx_articles_search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE projects ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    search_document(title, 'A')
+    || search_document(description, 'B')
+    || setweight(to_tsvector('pg_catalog.simple', search_array_text(tech_stack)), 'C')
+) STORED;
+
+ALTER TABLE articles ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    search_document(title, 'A')
+    || search_document(excerpt, 'B')
+    || search_document(content, 'B')
+    || setweight(to_tsvector('pg_catalog.simple', coalesce(category, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN(search_vector);
+
+DROP TRIGGER IF EXISTS users_search_author_name ON users;
+DROP TRIGGER IF EXISTS articles_search_author_name ON articles;
+DROP TRIGGER IF EXISTS projects_search_author_name ON projects;
+DROP FUNCTION IF EXISTS refresh_search_author_name();
+DROP FUNCTION IF EXISTS set_search_author_name();
+DROP FUNCTION IF EXISTS search_author_name(UUID);
+
+ALTER TABLE articles DROP COLUMN IF EXISTS author_name;
+ALTER TABLE projects DROP COLUMN IF EXISTS author_name;
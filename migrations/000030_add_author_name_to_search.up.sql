@@ -0,0 +1,71 @@
+-- The author's name and username are copied onto projects and articles so that they are
+-- part of each document's search vector instead of a separate match against users
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS author_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS author_name TEXT NOT NULL DEFAULT '';
+
+CREATE OR REPLACE FUNCTION search_author_name(author_id UUID) RETURNS TEXT AS $$
+    SELECT coalesce(trim(coalesce(name, '') || ' ' || coalesce(username, '')), '')
+    FROM users WHERE id = author_id
+$$ LANGUAGE SQL STABLE;
+
+UPDATE projects SET author_name = coalesce(search_author_name(user_id), '');
+UPDATE articles SET author_name = coalesce(search_author_name(user_id), '');
+
+-- New documents and ownership transfers take the name of their author
+CREATE OR REPLACE FUNCTION set_search_author_name() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.author_name := coalesce(search_author_name(NEW.user_id), '');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS projects_search_author_name ON projects;
+CREATE TRIGGER projects_search_author_name BEFORE INSERT OR UPDATE OF user_id ON projects
+    FOR EACH ROW EXECUTE FUNCTION set_search_author_name();
+
+DROP TRIGGER IF EXISTS articles_search_author_name ON articles;
+CREATE TRIGGER articles_search_author_name BEFORE INSERT OR UPDATE OF user_id ON articles
+    FOR EACH ROW EXECUTE FUNCTION set_search_author_name();
+
+-- Renaming a user updates the documents they own
+CREATE OR REPLACE FUNCTION refresh_search_author_name() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE projects SET author_name = search_author_name(NEW.id) WHERE user_id = NEW.id;
+    UPDATE articles SET author_name = search_author_name(NEW.id) WHERE user_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_search_author_name ON users;
+CREATE TRIGGER users_search_author_name AFTER UPDATE OF name, username ON users
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name OR OLD.username IS DISTINCT FROM NEW.username)
+    EXECUTE FUNCTION refresh_search_author_name();
+
+-- Generated columns cannot be altered, so the vectors are rebuilt with the author at weight D
+DROP INDEX IF EXISTS idx_projects_search_vector;
+DROP INDEX IF EXISTS idx_articles_search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE projects ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    search_document(title, 'A')
+    || search_document(description, 'B')
+    || setweight(to_tsvector('pg_catalog.simple', search_array_text(tech_stack)), 'C')
+    || search_document(author_name, 'D')
+) STORED;
+
+ALTER TABLE articles ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    search_document(title, 'A')
+    || search_document(excerpt, 'B')
+    || search_document(content, 'B')
+    || setweight(to_tsvector('pg_catalog.simple', coalesce(category, '')), 'C')
+    || search_document(author_name, 'D')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN(search_vector);
+
+-- Authors are no longer matched on their own
+DROP INDEX IF EXISTS idx_users_search_vector;
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS download_tokens (
+    id UUID PRIMARY KEY,
+    asset_id UUID NOT NULL REFERENCES release_assets(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_download_tokens_expires_at ON download_tokens(expires_at);